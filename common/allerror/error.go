@@ -13,7 +13,19 @@ const (
 
 	ErrorCodeNotImporter    = "software_pkg_not_importer"
 	ErrorCodeCIIsRunning    = "software_pkg_ci_is_running"
+	ErrorCodeCINotPassed    = "software_pkg_ci_not_passed"
 	ErrorCodeIncorrectPhase = "software_pkg_incorrect_phase"
+	ErrorCodeReopenExpired  = "software_pkg_reopen_expired"
+
+	ErrorCodeSpecNameMismatch = "software_pkg_spec_name_mismatch"
+
+	ErrorCodePkgNameUppercase     = "software_pkg_name_uppercase"
+	ErrorCodePkgNameReserved      = "software_pkg_name_reserved"
+	ErrorCodePkgNameBlocked       = "software_pkg_name_blocked"
+	ErrorCodePkgNameWrongPrefix   = "software_pkg_name_wrong_prefix"
+	ErrorCodePkgNameMissingPrefix = "software_pkg_name_missing_prefix"
+
+	ErrorCodeUnknownCheckItem = "software_pkg_unknown_check_item"
 )
 
 // errorImpl
@@ -43,3 +43,10 @@ func SendFailedResp(ctx *gin.Context, code string, err error) {
 		)
 	}
 }
+
+func SendFailedRespWithData(ctx *gin.Context, code string, err error, data interface{}) {
+	ctx.JSON(
+		http.StatusBadRequest,
+		ResponseData{Code: code, Msg: err.Error(), Data: data},
+	)
+}
@@ -0,0 +1,115 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const maxRedirects = 10
+
+// Client fetches the file at the url given by the applicant. It only
+// fetches the allowed hosts and never connects to the loopback, private
+// or link-local addresses, including the ones which the redirects point to.
+type Client struct {
+	cli   http.Client
+	hosts []string
+}
+
+func NewClient(cfg *Config, timeout time.Duration) *Client {
+	c := &Client{
+		hosts: make([]string, len(cfg.AllowedHosts)),
+	}
+
+	for i, h := range cfg.AllowedHosts {
+		c.hosts[i] = strings.ToLower(h)
+	}
+
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		// the address has been resolved when it is checked, so the
+		// host can't be rebound to a forbidden address.
+		Control: checkAddress,
+	}
+
+	c.cli = http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// the proxy is not used, otherwise the address of the
+			// proxy rather than the host would be checked.
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+
+			return c.checkURL(req.URL)
+		},
+	}
+
+	return c
+}
+
+// Get sends the request if the url is allowed.
+func (c *Client) Get(v string) (*http.Response, error) {
+	u, err := url.Parse(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkURL(u); err != nil {
+		return nil, err
+	}
+
+	return c.cli.Get(v)
+}
+
+func (c *Client) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("unsupported scheme: " + u.Scheme)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, h := range c.hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return nil
+		}
+	}
+
+	return errors.New("the host is not allowed: " + host)
+}
+
+func checkAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return errors.New("invalid ip: " + host)
+	}
+
+	if isForbiddenIP(ip) {
+		return errors.New("the address is forbidden: " + host)
+	}
+
+	return nil
+}
+
+func isForbiddenIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast()
+}
@@ -0,0 +1,103 @@
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestCheckURL(t *testing.T) {
+	cfg := Config{AllowedHosts: []string{"gitee.com", "GitHub.com"}}
+	c := NewClient(&cfg, time.Second)
+
+	cases := []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://gitee.com/a/b.spec", true},
+		{"http://gitee.com:8080/a/b.spec", true},
+		{"https://raw.github.com/a/b.spec", true},
+		{"https://GITHUB.com/a/b.spec", true},
+		{"file:///etc/passwd", false},
+		{"ftp://gitee.com/a/b.spec", false},
+		{"https://evilgitee.com/a/b.spec", false},
+		{"https://gitee.com.evil.com/a/b.spec", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://127.0.0.1/a.spec", false},
+	}
+
+	for _, item := range cases {
+		u, err := url.Parse(item.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := c.checkURL(u); (err == nil) != item.allowed {
+			t.Errorf("checkURL(%s) = %v, want allowed: %t", item.url, err, item.allowed)
+		}
+	}
+}
+
+func TestIsForbiddenIP(t *testing.T) {
+	cases := []struct {
+		ip        string
+		forbidden bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fc00::1", true},
+		{"0.0.0.0", true},
+		{"224.0.0.1", true},
+		{"8.8.8.8", false},
+		{"2001:4860:4860::8888", false},
+	}
+
+	for _, item := range cases {
+		if v := isForbiddenIP(net.ParseIP(item.ip)); v != item.forbidden {
+			t.Errorf("isForbiddenIP(%s) = %t, want %t", item.ip, v, item.forbidden)
+		}
+	}
+}
+
+func TestDialForbiddenAddress(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+
+	// the host is allowed, but the address it is resolved to is not.
+	cfg := Config{AllowedHosts: []string{"localhost"}}
+	c := NewClient(&cfg, time.Second)
+
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Get("http://localhost:" + u.Port()); err == nil {
+		t.Error("the loopback address should not be connected")
+	}
+}
+
+func TestRedirectToForbiddenHost(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+
+	cfg := Config{AllowedHosts: []string{"gitee.com"}}
+	c := NewClient(&cfg, time.Second)
+
+	req, err := http.NewRequest(http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.cli.CheckRedirect(req, nil); err == nil {
+		t.Error("the redirect to the host which is not allowed should be stopped")
+	}
+}
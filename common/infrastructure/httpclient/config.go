@@ -0,0 +1,35 @@
+package httpclient
+
+import (
+	"errors"
+	"strings"
+)
+
+// Config restricts the hosts which can be fetched, because the urls are
+// given by the applicants.
+type Config struct {
+	// AllowedHosts are the hosts of the urls which can be fetched.
+	// The sub-domains of them are allowed too.
+	AllowedHosts []string `json:"allowed_hosts"`
+}
+
+func (cfg *Config) SetDefault() {
+	if len(cfg.AllowedHosts) == 0 {
+		cfg.AllowedHosts = []string{
+			"gitee.com",
+			"github.com",
+			"githubusercontent.com",
+			"openeuler.org",
+		}
+	}
+}
+
+func (cfg *Config) Validate() error {
+	for _, h := range cfg.AllowedHosts {
+		if h == "" || strings.ContainsAny(h, "/:") {
+			return errors.New("invalid allowed host: " + h)
+		}
+	}
+
+	return nil
+}
@@ -45,10 +45,43 @@ type ColumnFilter struct {
 	column string
 	symbol string
 	value  interface{}
+
+	// or is set if it is the disjunction of the filters.
+	or []ColumnFilter
 }
 
 func (q *ColumnFilter) condition() string {
-	return fmt.Sprintf("%s %s ?", q.column, q.symbol)
+	if len(q.or) == 0 {
+		if q.symbol == "between" {
+			return fmt.Sprintf("%s between ? and ?", q.column)
+		}
+
+		return fmt.Sprintf("%s %s ?", q.column, q.symbol)
+	}
+
+	v := make([]string, len(q.or))
+	for i := range q.or {
+		v[i] = q.or[i].condition()
+	}
+
+	return "(" + strings.Join(v, " or ") + ")"
+}
+
+func (q *ColumnFilter) args() []interface{} {
+	if len(q.or) == 0 {
+		if v, ok := q.value.([2]interface{}); ok && q.symbol == "between" {
+			return v[:]
+		}
+
+		return []interface{}{q.value}
+	}
+
+	var v []interface{}
+	for i := range q.or {
+		v = append(v, q.or[i].args()...)
+	}
+
+	return v
 }
 
 func NewEqualFilter(column string, value interface{}) ColumnFilter {
@@ -67,18 +100,65 @@ func NewLikeFilter(column string, value string) ColumnFilter {
 	}
 }
 
+// NewInFilter filters the records whose column is one of the values.
+func NewInFilter(column string, values interface{}) ColumnFilter {
+	return ColumnFilter{
+		column: column,
+		symbol: "in",
+		value:  values,
+	}
+}
+
+// NewBetweenFilter filters the records whose column is in the closed range.
+func NewBetweenFilter(column string, min, max interface{}) ColumnFilter {
+	return ColumnFilter{
+		column: column,
+		symbol: "between",
+		value:  [2]interface{}{min, max},
+	}
+}
+
+// NewOrFilter filters the records which match any one of the filters.
+func NewOrFilter(filters ...ColumnFilter) ColumnFilter {
+	return ColumnFilter{or: filters}
+}
+
 type dbTable struct {
 	name string
+	// tx is set when the table is operated in a transaction
+	tx *gorm.DB
 }
 
 func NewDBTable(name string) dbTable {
 	return dbTable{name: name}
 }
 
+func (t dbTable) conn() *gorm.DB {
+	if t.tx != nil {
+		return t.tx
+	}
+
+	return db
+}
+
+// WithTx returns the table which will be operated in the transaction.
+func (t dbTable) WithTx(tx Tx) TxTable {
+	t.tx = tx.db
+
+	return t
+}
+
+// Insert inserts the result if no record matches the filter. It returns the
+// error of row exists if there is one, including the one inserted concurrently
+// which violates the unique key.
 func (t dbTable) Insert(filter, result interface{}) error {
-	query := db.Table(t.name).Where(filter).FirstOrCreate(result)
+	query := t.conn().Table(t.name).Where(filter).FirstOrCreate(result)
 
 	if err := query.Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return errRowExists
+		}
+
 		return err
 	}
 
@@ -90,7 +170,7 @@ func (t dbTable) Insert(filter, result interface{}) error {
 }
 
 func (t dbTable) InsertWithNot(filter, notFilter, result interface{}) error {
-	query := db.Table(t.name).
+	query := t.conn().Table(t.name).
 		Where(filter).
 		Not(notFilter).
 		FirstOrCreate(result)
@@ -110,9 +190,9 @@ func (t dbTable) GetRecords(
 	filter []ColumnFilter, result interface{}, p Pagination,
 	sort []SortByColumn,
 ) (err error) {
-	query := db.Table(t.name)
+	query := t.conn().Table(t.name)
 	for i := range filter {
-		query.Where(filter[i].condition(), filter[i].value)
+		query.Where(filter[i].condition(), filter[i].args()...)
 	}
 
 	var orders []string
@@ -135,9 +215,9 @@ func (t dbTable) GetRecords(
 
 func (t dbTable) Count(filter []ColumnFilter) (int, error) {
 	var total int64
-	query := db.Table(t.name)
+	query := t.conn().Table(t.name)
 	for i := range filter {
-		query.Where(filter[i].condition(), filter[i].value)
+		query.Where(filter[i].condition(), filter[i].args()...)
 	}
 
 	err := query.Count(&total).Error
@@ -146,7 +226,7 @@ func (t dbTable) Count(filter []ColumnFilter) (int, error) {
 }
 
 func (t dbTable) GetRecord(filter, result interface{}) error {
-	err := db.Table(t.name).Where(filter).First(result).Error
+	err := t.conn().Table(t.name).Where(filter).First(result).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return errRowNotFound
 	}
@@ -155,7 +235,7 @@ func (t dbTable) GetRecord(filter, result interface{}) error {
 }
 
 func (t dbTable) UpdateRecord(filter, update interface{}) (err error) {
-	query := db.Table(t.name).Where(filter).Updates(update)
+	query := t.conn().Table(t.name).Where(filter).Updates(update)
 	if err = query.Error; err != nil {
 		return
 	}
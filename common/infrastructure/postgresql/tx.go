@@ -0,0 +1,26 @@
+package postgresql
+
+import "gorm.io/gorm"
+
+// Tx is a db transaction
+type Tx struct {
+	db *gorm.DB
+}
+
+// TxTable is the table which is operated in a transaction.
+type TxTable interface {
+	Insert(filter, result interface{}) error
+	InsertWithNot(filter, notFilter, result interface{}) error
+	UpdateRecord(filter, update interface{}) error
+
+	IsRowNotFound(error) bool
+	IsRowExists(error) bool
+}
+
+// Transaction executes f in a transaction. The transaction will be
+// committed if f returns nil, otherwise it will be rolled back.
+func Transaction(f func(Tx) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		return f(Tx{db: tx})
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/opensourceways/software-package-server/common/controller/middleware"
 	"github.com/opensourceways/software-package-server/common/infrastructure/postgresql"
+	"github.com/opensourceways/software-package-server/softwarepkg/app"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/clavalidatorimpl"
@@ -14,6 +15,7 @@ import (
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/repositoryimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/sensitivewordsimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/sigvalidatorimpl"
+	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/specparserimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/translationimpl"
 	localutils "github.com/opensourceways/software-package-server/utils"
 )
@@ -64,6 +66,9 @@ type Config struct {
 	Translation    translationimpl.Config    `json:"translation"          required:"true"`
 	SigValidator   sigvalidatorimpl.Config   `json:"sig"                  required:"true"`
 	SensitiveWords sensitivewordsimpl.Config `json:"sensitive_words"      required:"true"`
+	SpecParser     specparserimpl.Config     `json:"spec_parser"`
+	OutboxRelay    localutils.BackoffConfig  `json:"outbox_relay"`
+	CLAChecking    app.CLAConfig             `json:"cla_checking"`
 }
 
 func (cfg *Config) configItems() []interface{} {
@@ -81,6 +86,9 @@ func (cfg *Config) configItems() []interface{} {
 		&cfg.Maintainer,
 		&cfg.Translation,
 		&cfg.SigValidator,
+		&cfg.SpecParser,
+		&cfg.OutboxRelay,
+		&cfg.CLAChecking,
 	}
 }
 
@@ -193,6 +193,124 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/softwarepkg/checkitems": {
+            "get": {
+                "description": "list the check items that software package will be reviewed against",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SoftwarePkg"
+                ],
+                "summary": "list check items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sig of the softwarePkg",
+                        "name": "sig",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "platform of the softwarePkg",
+                        "name": "platform",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.CheckItemDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/softwarepkg/similar": {
+            "get": {
+                "description": "list the imported and in-flight software packages which may be the same software",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SoftwarePkg"
+                ],
+                "summary": "list similar software packages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "name of the softwarePkg",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "upstream of the softwarePkg",
+                        "name": "upstream",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.SimilarSoftwarePkgDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/softwarepkg/validate": {
+            "post": {
+                "description": "check the application as applying it, and return all the problems at once. Nothing will be saved.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SoftwarePkg"
+                ],
+                "summary": "validate the application of a new software package",
+                "parameters": [
+                    {
+                        "description": "body of applying a new software package",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.softwarePkgRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.ApplicationValidationDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/softwarepkg/{id}": {
             "get": {
                 "description": "get software package",
@@ -228,6 +346,191 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/softwarepkg/{id}/dependencies": {
+            "get": {
+                "description": "list the BuildRequires and Requires of the spec and whether they are available",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SoftwarePkg"
+                ],
+                "summary": "list dependencies of software package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of software package",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.SoftwarePkgDependenciesDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/softwarepkg/{id}/reopen": {
+            "put": {
+                "description": "reopen the closed software package. The importer can reopen it within a period after it was closed, and tc can reopen it at any time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SoftwarePkg"
+                ],
+                "summary": "reopen software package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of software package",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/softwarepkg/{id}/retire": {
+            "put": {
+                "description": "retire the imported software package, only tc can do it",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SoftwarePkg"
+                ],
+                "summary": "retire software package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of software package",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/softwarepkg/{id}/review": {
+            "get": {
+                "description": "get the review result of each check item of software package",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SoftwarePkg"
+                ],
+                "summary": "get review of software package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of software package",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/app.CheckItemReviewDTO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "review the check items of software package",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SoftwarePkg"
+                ],
+                "summary": "review software package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of software package",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "body of reviewing software package",
+                        "name": "param",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/controller.reviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/softwarepkg/{id}/review/abandon": {
             "put": {
                 "description": "abandon software package",
@@ -300,7 +603,7 @@ const docTemplate = `{
         },
         "/v1/softwarepkg/{id}/review/comment": {
             "post": {
-                "description": "create a new software package review comment",
+                "description": "create a new software package review comment, the commands in it such as /approve, /reject, /lgtm, /check, /rerun-ci and /abandon will be executed",
                 "consumes": [
                     "application/json"
                 ],
@@ -393,83 +696,372 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/softwarepkg/{id}/review/reject": {
-            "put": {
-                "description": "reject software package",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "SoftwarePkg"
-                ],
-                "summary": "reject software package",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "id of software package",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "202": {
-                        "description": "Accepted",
-                        "schema": {
-                            "$ref": "#/definitions/controller.ResponseData"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controller.ResponseData"
-                        }
+        "/v1/softwarepkg/{id}/review/reject": {
+            "put": {
+                "description": "reject software package",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SoftwarePkg"
+                ],
+                "summary": "reject software package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of software package",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/softwarepkg/{id}/review/rerunci": {
+            "put": {
+                "description": "rerun ci of software package",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SoftwarePkg"
+                ],
+                "summary": "rerun ci of software package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of software package",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/softwarepkg/{id}/revisions": {
+            "get": {
+                "description": "list the revisions of the application of software package",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SoftwarePkg"
+                ],
+                "summary": "list revisions of software package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of software package",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.SoftwarePkgRevisionDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/softwarepkg/{id}/revisions/diff": {
+            "get": {
+                "description": "list the fields of application which are changed between two revisions",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SoftwarePkg"
+                ],
+                "summary": "diff two revisions of software package",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of software package",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of the old revision",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of the new revision",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/app.ApplicationFieldDiffDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/controller.ResponseData"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "app.ApplicationFieldDiffDTO": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "new": {
+                    "type": "string"
+                },
+                "old": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.ApplicationProblemDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "msg": {
+                    "type": "string"
+                },
+                "segments": {
+                    "description": "Segments are the sensitive parts of the content if it is sensitive.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "app.ApplicationValidationDTO": {
+            "type": "object",
+            "properties": {
+                "problems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.ApplicationProblemDTO"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "app.CheckItemDTO": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "item": {
+                    "type": "string"
+                },
+                "owners": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "app.CheckItemReviewDTO": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "item": {
+                    "type": "string"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.CheckItemReviewInfoDTO"
+                    }
+                }
+            }
+        },
+        "app.CheckItemReviewInfoDTO": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "string"
+                },
+                "desc": {
+                    "type": "string"
+                },
+                "outdated": {
+                    "description": "Outdated means the review was made against an older revision.",
+                    "type": "boolean"
+                },
+                "pass": {
+                    "type": "boolean"
+                },
+                "revision": {
+                    "description": "Revision is the revision of application which the review was made against.",
+                    "type": "integer"
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "stale": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "app.LicenseCheckDTO": {
+            "type": "object",
+            "properties": {
+                "check_item": {
+                    "type": "integer"
+                },
+                "compliant": {
+                    "type": "boolean"
+                },
+                "license": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "app.NewSoftwarePkgDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "similar_pkgs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.SimilarSoftwarePkgDTO"
                     }
+                },
+                "warning": {
+                    "description": "Warning is set if there are pkgs which may be the same software.",
+                    "type": "string"
                 }
             }
         },
-        "/v1/softwarepkg/{id}/review/rerunci": {
-            "put": {
-                "description": "rerun ci of software package",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "SoftwarePkg"
-                ],
-                "summary": "rerun ci of software package",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "id of software package",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+        "app.ReviewQuorumDTO": {
+            "type": "object",
+            "properties": {
+                "approved_by_sig_maintainer": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
-                ],
-                "responses": {
-                    "202": {
-                        "description": "Accepted",
-                        "schema": {
-                            "$ref": "#/definitions/controller.ResponseData"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/controller.ResponseData"
-                        }
+                },
+                "approved_by_tc": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "min_num_approved_by_sig_maintainer": {
+                    "type": "integer"
+                },
+                "min_num_approved_by_tc": {
+                    "type": "integer"
+                },
+                "reached": {
+                    "type": "boolean"
+                },
+                "vetoed_by_tc": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "app.NewSoftwarePkgDTO": {
+        },
+        "app.SimilarSoftwarePkgDTO": {
             "type": "object",
             "properties": {
+                "distance": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "string"
+                },
+                "phase": {
+                    "type": "string"
+                },
+                "pkg_name": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "upstream": {
+                    "type": "string"
                 }
             }
         },
@@ -479,6 +1071,9 @@ const docTemplate = `{
                 "desc": {
                     "type": "string"
                 },
+                "license": {
+                    "type": "string"
+                },
                 "platform": {
                     "type": "string"
                 },
@@ -513,6 +1108,13 @@ const docTemplate = `{
         "app.SoftwarePkgBasicInfoDTO": {
             "type": "object",
             "properties": {
+                "allowed_actions": {
+                    "description": "AllowedActions are the actions which can be done in current phase.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "applied_at": {
                     "type": "string"
                 },
@@ -540,17 +1142,67 @@ const docTemplate = `{
                 "repo_link": {
                     "type": "string"
                 },
+                "revision": {
+                    "type": "integer"
+                },
                 "sig": {
                     "type": "string"
                 }
             }
         },
+        "app.SoftwarePkgDependenciesDTO": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/app.SoftwarePkgDependencyDTO"
+                    }
+                },
+                "missing": {
+                    "description": "Missing is the number of dependencies which are not available in the\ndistribution yet, including the ones whose applications are in progress.",
+                    "type": "integer"
+                }
+            }
+        },
+        "app.SoftwarePkgDependencyDTO": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pkg_id": {
+                    "type": "string"
+                },
+                "requirement": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
         "app.SoftwarePkgOperationLogDTO": {
             "type": "object",
             "properties": {
                 "action": {
                     "type": "string"
                 },
+                "ci_pr_num": {
+                    "type": "integer"
+                },
+                "new_phase": {
+                    "type": "string"
+                },
+                "old_phase": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
                 "time": {
                     "type": "string"
                 },
@@ -582,6 +1234,13 @@ const docTemplate = `{
         "app.SoftwarePkgReviewDTO": {
             "type": "object",
             "properties": {
+                "allowed_actions": {
+                    "description": "AllowedActions are the actions which can be done in current phase.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "application": {
                     "$ref": "#/definitions/app.SoftwarePkgApplicationDTO"
                 },
@@ -612,6 +1271,9 @@ const docTemplate = `{
                 "importer": {
                     "type": "string"
                 },
+                "license_check": {
+                    "$ref": "#/definitions/app.LicenseCheckDTO"
+                },
                 "logs": {
                     "type": "array",
                     "items": {
@@ -627,6 +1289,9 @@ const docTemplate = `{
                 "platform": {
                     "type": "string"
                 },
+                "quorum": {
+                    "$ref": "#/definitions/app.ReviewQuorumDTO"
+                },
                 "rejected_by": {
                     "type": "array",
                     "items": {
@@ -636,8 +1301,81 @@ const docTemplate = `{
                 "repo_link": {
                     "type": "string"
                 },
+                "revision": {
+                    "type": "integer"
+                },
                 "sig": {
                     "type": "string"
+                },
+                "spec": {
+                    "$ref": "#/definitions/app.SoftwarePkgSpecDTO"
+                },
+                "src_rpm_notes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "app.SoftwarePkgRevisionDTO": {
+            "type": "object",
+            "properties": {
+                "application": {
+                    "$ref": "#/definitions/app.SoftwarePkgApplicationDTO"
+                },
+                "author": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "app.SoftwarePkgSpecDTO": {
+            "type": "object",
+            "properties": {
+                "build_requires": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "license": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "patches": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "requires": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sources": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "type": "string"
                 }
             }
         },
@@ -675,6 +1413,20 @@ const docTemplate = `{
                 }
             }
         },
+        "controller.checkItemReviewRequest": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "pass": {
+                    "type": "boolean"
+                }
+            }
+        },
         "controller.claSingedResp": {
             "type": "object",
             "properties": {
@@ -694,6 +1446,20 @@ const docTemplate = `{
                 }
             }
         },
+        "controller.reviewRequest": {
+            "type": "object",
+            "required": [
+                "reviews"
+            ],
+            "properties": {
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/controller.checkItemReviewRequest"
+                    }
+                }
+            }
+        },
         "controller.softwarePkgRequest": {
             "type": "object",
             "required": [
@@ -710,6 +1476,9 @@ const docTemplate = `{
                 "desc": {
                     "type": "string"
                 },
+                "license": {
+                    "type": "string"
+                },
                 "pkg_name": {
                     "type": "string"
                 },
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
 
@@ -12,14 +13,17 @@ import (
 	"github.com/opensourceways/software-package-server/common/infrastructure/postgresql"
 	"github.com/opensourceways/software-package-server/config"
 	"github.com/opensourceways/software-package-server/server"
+	"github.com/opensourceways/software-package-server/softwarepkg/app"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/clavalidatorimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/maintainerimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/messageimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/pkgmanagerimpl"
+	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/repositoryimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/sensitivewordsimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/sigvalidatorimpl"
+	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/specparserimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/translationimpl"
 	"github.com/opensourceways/software-package-server/utils"
 )
@@ -117,6 +121,9 @@ func main() {
 		return
 	}
 
+	// Spec parser
+	specparserimpl.Init(&cfg.SpecParser)
+
 	// Encryption
 	if err = utils.InitEncryption(cfg.Encryption.EncryptionKey); err != nil {
 		logrus.Errorf("init encryption failed, err:%s", err.Error())
@@ -133,6 +140,16 @@ func main() {
 
 	defer messageimpl.Exit()
 
+	// Outbox relay
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	outbox := app.NewSoftwarePkgOutboxService(
+		repositoryimpl.NewSoftwarePkgOutbox(&cfg.Postgresql.Config),
+		messageimpl.Producer(),
+	)
+	go utils.RunWithBackoff(ctx, &cfg.OutboxRelay, outbox.PublishPendingEvents)
+
 	// Maintainer
 	if err := maintainerimpl.Init(&cfg.Maintainer); err != nil {
 		logrus.Errorf("init maintainer failed, err:%s", err.Error())
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/app"
+)
+
+type ciTimeoutCheckerConfig struct {
+	// Interval is the interval in seconds to check the ci which is timeout.
+	Interval int `json:"interval"`
+}
+
+func (cfg *ciTimeoutCheckerConfig) SetDefault() {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 300
+	}
+}
+
+func (cfg *ciTimeoutCheckerConfig) interval() time.Duration {
+	return time.Duration(cfg.Interval) * time.Second
+}
+
+// ciTimeoutChecker checks the ci periodically and marks the one
+// which has run too long as timeout.
+type ciTimeoutChecker struct {
+	service app.SoftwarePkgMessageService
+}
+
+func (c *ciTimeoutChecker) run(ctx context.Context, cfg *ciTimeoutCheckerConfig) {
+	t := time.NewTicker(cfg.interval())
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("ci timeout checker exits")
+
+			return
+
+		case <-t.C:
+			if err := c.service.HandleCITimeout(); err != nil {
+				logrus.Errorf("failed to handle ci timeout, err:%s", err.Error())
+			}
+		}
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/pkgmanagerimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/repositoryimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/sigvalidatorimpl"
+	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/specparserimpl"
+	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/srpmreaderimpl"
 	localutils "github.com/opensourceways/software-package-server/utils"
 )
 
@@ -52,6 +54,14 @@ type Config struct {
 	TopicsToNotify TopicsToNotify          `json:"topics_to_notify"     required:"true"`
 	SigValidator   sigvalidatorimpl.Config `json:"sig"                  required:"true"`
 	PkgCI          pkgciimpl.Config        `json:"ci"                   required:"true"`
+	DeadLetter     deadLetterConfig        `json:"dead_letter"          required:"true"`
+	ProcessedEvent processedEventConfig    `json:"processed_event"      required:"true"`
+	Retry          retryConfig             `json:"retry"`
+	SpecParser     specparserimpl.Config   `json:"spec_parser"`
+	SrcRPMReader   srpmreaderimpl.Config   `json:"src_rpm_reader"`
+
+	OutboxRelay      localutils.BackoffConfig `json:"outbox_relay"`
+	CITimeoutChecker ciTimeoutCheckerConfig   `json:"ci_timeout_checker"`
 }
 
 type Topics struct {
@@ -87,6 +97,12 @@ func (cfg *Config) configItems() []interface{} {
 		&cfg.PkgManager,
 		&cfg.SigValidator,
 		&cfg.PkgCI,
+		&cfg.ProcessedEvent,
+		&cfg.Retry,
+		&cfg.SpecParser,
+		&cfg.SrcRPMReader,
+		&cfg.OutboxRelay,
+		&cfg.CITimeoutChecker,
 	}
 }
 
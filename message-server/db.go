@@ -0,0 +1,13 @@
+package main
+
+import "github.com/opensourceways/software-package-server/common/infrastructure/postgresql"
+
+type dbClient interface {
+	Insert(filter, result interface{}) error
+	GetRecords([]postgresql.ColumnFilter, interface{}, postgresql.Pagination, []postgresql.SortByColumn) error
+	GetRecord(filter, result interface{}) error
+	UpdateRecord(filter, update interface{}) error
+
+	IsRowNotFound(error) bool
+	IsRowExists(error) bool
+}
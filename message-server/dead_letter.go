@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/software-package-server/common/infrastructure/kafka"
+	"github.com/opensourceways/software-package-server/common/infrastructure/postgresql"
+	"github.com/opensourceways/software-package-server/utils"
+)
+
+const (
+	fieldError      = "error"
+	fieldAttempts   = "attempts"
+	fieldFailedAt   = "failed_at"
+	fieldReplayedAt = "replayed_at"
+)
+
+type deadLetterConfig struct {
+	// Topic is the dead letter topic which the failed messages will be sent to.
+	Topic string `json:"topic" required:"true"`
+
+	// Table is the table which the messages of dead letter topic will be saved to.
+	Table string `json:"table" required:"true"`
+}
+
+// deadLetterMsg is the message sent to the dead letter topic.
+type deadLetterMsg struct {
+	Topic    string `json:"topic"`
+	Payload  []byte `json:"payload"`
+	Error    string `json:"error"`
+	Attempts int    `json:"attempts"`
+	FailedAt int64  `json:"failed_at"`
+}
+
+// deadLetterDO
+type deadLetterDO struct {
+	// must set "uuid" as the name of column
+	Id         uuid.UUID `gorm:"column:uuid;type:uuid"`
+	Topic      string    `gorm:"column:topic"`
+	Payload    []byte    `gorm:"column:payload"`
+	Error      string    `gorm:"column:error"`
+	Attempts   int       `gorm:"column:attempts"`
+	FailedAt   int64     `gorm:"column:failed_at"`
+	ReplayedAt int64     `gorm:"column:replayed_at"`
+}
+
+func newDeadLetterService(cfg *deadLetterConfig) deadLetterService {
+	return deadLetterService{
+		topic: cfg.Topic,
+		dbCli: postgresql.NewDBTable(cfg.Table),
+	}
+}
+
+type deadLetterService struct {
+	topic string
+	dbCli dbClient
+}
+
+// send sends the message which failed to be handled to the dead letter topic.
+func (s deadLetterService) send(topic string, payload []byte, err error, attempts int) {
+	msg := deadLetterMsg{
+		Topic:    topic,
+		Payload:  payload,
+		Error:    err.Error(),
+		Attempts: attempts,
+		FailedAt: utils.Now(),
+	}
+
+	v, err := json.Marshal(&msg)
+	if err == nil {
+		err = kafka.Publish(s.topic, v)
+	}
+
+	if err != nil {
+		logrus.Errorf(
+			"failed to send message of topic:%s to dead letter topic, err:%s",
+			topic, err.Error(),
+		)
+	}
+}
+
+// save saves the message of dead letter topic, so that it can be listed and replayed.
+func (s deadLetterService) save(data []byte) error {
+	var msg deadLetterMsg
+
+	if err := json.Unmarshal(data, &msg); err != nil {
+		logrus.Errorf("failed to unmarshal dead letter, err:%s", err.Error())
+
+		return nil
+	}
+
+	do := deadLetterDO{
+		Id:       uuid.New(),
+		Topic:    msg.Topic,
+		Payload:  msg.Payload,
+		Error:    msg.Error,
+		Attempts: msg.Attempts,
+		FailedAt: msg.FailedAt,
+	}
+
+	return s.dbCli.Insert(&deadLetterDO{Id: do.Id}, &do)
+}
+
+// list writes the dead letters which are not replayed to w.
+func (s deadLetterService) list(w io.Writer) error {
+	var dos []deadLetterDO
+
+	err := s.dbCli.GetRecords(
+		[]postgresql.ColumnFilter{
+			postgresql.NewEqualFilter(fieldReplayedAt, 0),
+		},
+		&dos,
+		postgresql.Pagination{},
+		[]postgresql.SortByColumn{
+			{Column: fieldFailedAt, Ascend: true},
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for i := range dos {
+		item := &dos[i]
+
+		fmt.Fprintf(
+			w, "%s\t%s\t%s\t%d\t%s\n",
+			item.Id.String(), item.Topic, utils.ToDateTime(item.FailedAt),
+			item.Attempts, item.Error,
+		)
+	}
+
+	return nil
+}
+
+// replay handles the dead letter by the handler of its original topic.
+func (s deadLetterService) replay(id string, handlers map[string]kafka.Handler) error {
+	v, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	filter := deadLetterDO{Id: v}
+
+	var do deadLetterDO
+	if err := s.dbCli.GetRecord(&filter, &do); err != nil {
+		return err
+	}
+
+	if do.ReplayedAt > 0 {
+		return fmt.Errorf("the dead letter:%s was replayed", id)
+	}
+
+	h, ok := handlers[do.Topic]
+	if !ok {
+		return fmt.Errorf("no handler for topic:%s", do.Topic)
+	}
+
+	if err := h(do.Payload); err != nil {
+		// record the new failure
+		_ = s.dbCli.UpdateRecord(&filter, map[string]any{
+			fieldError:    err.Error(),
+			fieldAttempts: do.Attempts + 1,
+		})
+
+		return err
+	}
+
+	return s.dbCli.UpdateRecord(&filter, map[string]any{
+		fieldReplayedAt: utils.Now(),
+	})
+}
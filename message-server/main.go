@@ -21,12 +21,18 @@ import (
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/pkgmanagerimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/repositoryimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/sigvalidatorimpl"
+	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/specparserimpl"
+	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/srpmreaderimpl"
 	"github.com/opensourceways/software-package-server/utils"
 )
 
 type options struct {
 	service     liboptions.ServiceOptions
 	enableDebug bool
+
+	// the admin commands of dead letters
+	listDeadLetters  bool
+	replayDeadLetter string
 }
 
 func (o *options) Validate() error {
@@ -42,6 +48,16 @@ func gatherOptions(fs *flag.FlagSet, args ...string) options {
 		&o.enableDebug, "enable_debug", false, "whether to enable debug model.",
 	)
 
+	fs.BoolVar(
+		&o.listDeadLetters, "list_dead_letters", false,
+		"list the dead letters which are not replayed and exit.",
+	)
+
+	fs.StringVar(
+		&o.replayDeadLetter, "replay_dead_letter", "",
+		"replay the dead letter of the id by the handler of its original topic and exit.",
+	)
+
 	fs.Parse(args)
 	return o
 }
@@ -112,6 +128,10 @@ func main() {
 		return
 	}
 
+	// src.rpm reader
+	specparserimpl.Init(&cfg.SpecParser)
+	srpmreaderimpl.Init(&cfg.SrcRPMReader, specparserimpl.SpecParser())
+
 	// mq
 	if err = kafka.Init(&cfg.Kafka, log); err != nil {
 		logrus.Errorf("initialize mq failed, err:%v", err)
@@ -126,11 +146,44 @@ func main() {
 		pkgciimpl.PkgCI(),
 		repositoryimpl.NewSoftwarePkg(&cfg.Postgresql.Config),
 		pkgmanagerimpl.Instance(),
+		srpmreaderimpl.SrcRPMReader(),
+	)
+
+	defer messageService.Exit()
+
+	outboxService := app.NewSoftwarePkgIndirectOutboxService(
+		repositoryimpl.NewSoftwarePkgOutbox(&cfg.Postgresql.Config),
 		&producer{topics: cfg.TopicsToNotify},
 	)
 
+	s := &server{
+		service:     messageService,
+		outbox:      outboxService,
+		deadLetters: newDeadLetterService(&cfg.DeadLetter),
+		processed:   newProcessedEvents(cfg.GroupName, &cfg.ProcessedEvent),
+	}
+
+	// admin commands
+	if o.listDeadLetters {
+		if err := s.deadLetters.list(os.Stdout); err != nil {
+			logrus.Errorf("list dead letters failed, err:%s", err.Error())
+		}
+
+		return
+	}
+
+	if o.replayDeadLetter != "" {
+		if err := s.deadLetters.replay(o.replayDeadLetter, s.handlers(cfg)); err != nil {
+			logrus.Errorf("replay dead letter failed, err:%s", err.Error())
+		} else {
+			logrus.Infof("replay dead letter:%s successfully", o.replayDeadLetter)
+		}
+
+		return
+	}
+
 	// run
-	run(&server{messageService}, cfg)
+	run(s, cfg)
 }
 
 func run(s *server, cfg *Config) {
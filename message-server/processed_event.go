@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/software-package-server/common/infrastructure/kafka"
+	"github.com/opensourceways/software-package-server/common/infrastructure/postgresql"
+	"github.com/opensourceways/software-package-server/utils"
+)
+
+const (
+	eventStatusProcessing = "processing"
+	eventStatusDone       = "done"
+
+	fieldGroupName = "group_name"
+	fieldEventId   = "event_id"
+	fieldStatus    = "status"
+	fieldUpdatedAt = "updated_at"
+)
+
+type processedEventConfig struct {
+	// Table is the table which records the events processed by the consumer group.
+	// It must have a unique key of (group_name, event_id).
+	Table string `json:"table" required:"true"`
+
+	// Lease is the seconds that an event is held by the consumer processing it.
+	// The event can be processed by another one after the lease expires,
+	// such as the consumer crashed.
+	Lease int64 `json:"lease"`
+}
+
+func (cfg *processedEventConfig) SetDefault() {
+	if cfg.Lease <= 0 {
+		cfg.Lease = 600
+	}
+}
+
+// processedEventDO
+type processedEventDO struct {
+	// must set "uuid" as the name of column
+	Id        uuid.UUID `gorm:"column:uuid;type:uuid"`
+	GroupName string    `gorm:"column:group_name"`
+	EventId   string    `gorm:"column:event_id"`
+	Topic     string    `gorm:"column:topic"`
+	Status    string    `gorm:"column:status"`
+	CreatedAt int64     `gorm:"column:created_at"`
+	UpdatedAt int64     `gorm:"column:updated_at"`
+}
+
+func newProcessedEvents(group string, cfg *processedEventConfig) processedEvents {
+	return processedEvents{
+		group: group,
+		lease: cfg.Lease,
+		dbCli: postgresql.NewDBTable(cfg.Table),
+	}
+}
+
+// processedEvents is the ledger of the events which were processed by the consumer group.
+type processedEvents struct {
+	group string
+	lease int64
+	dbCli dbClient
+}
+
+func (p processedEvents) filter(eventId string) map[string]any {
+	return map[string]any{
+		fieldGroupName: p.group,
+		fieldEventId:   eventId,
+	}
+}
+
+// claim records the event as processing before handling it, so that only
+// one consumer can handle it. It returns false if the event was processed,
+// or it is being processed by another consumer whose lease doesn't expire.
+func (p processedEvents) claim(topic, eventId string) (bool, error) {
+	now := utils.Now()
+
+	do := processedEventDO{
+		Id:        uuid.New(),
+		GroupName: p.group,
+		EventId:   eventId,
+		Topic:     topic,
+		Status:    eventStatusProcessing,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	err := p.dbCli.Insert(
+		&processedEventDO{GroupName: p.group, EventId: eventId}, &do,
+	)
+	if err == nil || !p.dbCli.IsRowExists(err) {
+		return err == nil, err
+	}
+
+	var old processedEventDO
+	if err = p.dbCli.GetRecord(p.filter(eventId), &old); err != nil {
+		return false, err
+	}
+
+	if old.Status == eventStatusDone || old.UpdatedAt+p.lease > now {
+		return false, nil
+	}
+
+	// take over the event whose lease expired, only one can succeed.
+	filter := p.filter(eventId)
+	filter[fieldStatus] = eventStatusProcessing
+	filter[fieldUpdatedAt] = old.UpdatedAt
+
+	err = p.dbCli.UpdateRecord(filter, map[string]any{fieldUpdatedAt: now})
+	if err != nil && p.dbCli.IsRowNotFound(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// release makes the lease of event expire, so that it can be claimed again.
+func (p processedEvents) release(eventId string) error {
+	filter := p.filter(eventId)
+	filter[fieldStatus] = eventStatusProcessing
+
+	return p.dbCli.UpdateRecord(filter, map[string]any{fieldUpdatedAt: 0})
+}
+
+func (p processedEvents) done(eventId string) error {
+	return p.dbCli.UpdateRecord(
+		p.filter(eventId),
+		map[string]any{
+			fieldStatus:    eventStatusDone,
+			fieldUpdatedAt: utils.Now(),
+		},
+	)
+}
+
+// idempotent skips the message if it was processed by the consumer group.
+// The message without event id is identified by its pkg and pr, such as
+// the result of ci. The other ones, such as the ones sent by other
+// services, are always handled.
+func (p processedEvents) idempotent(topic string, h kafka.Handler) kafka.Handler {
+	return func(data []byte) error {
+		eventId := eventIdOf(topic, data)
+		if eventId == "" {
+			return h(data)
+		}
+
+		claimed, err := p.claim(topic, eventId)
+		if err != nil {
+			return err
+		}
+
+		if !claimed {
+			logrus.Infof("skip the processed event:%s of topic:%s", eventId, topic)
+
+			return nil
+		}
+
+		if err := h(data); err != nil {
+			if err1 := p.release(eventId); err1 != nil {
+				logrus.Errorf(
+					"failed to release the event:%s of topic:%s, err:%s",
+					eventId, topic, err1.Error(),
+				)
+			}
+
+			return err
+		}
+
+		if err := p.done(eventId); err != nil {
+			logrus.Errorf(
+				"failed to record the processed event:%s of topic:%s, err:%s",
+				eventId, topic, err.Error(),
+			)
+		}
+
+		return nil
+	}
+}
+
+// eventIdOf returns the event id of the message. The message of a pr of
+// pkg without event id, such as the result of ci, is identified by them,
+// because the pr is handled only once. It is empty if the message can't
+// be identified.
+func eventIdOf(topic string, data []byte) string {
+	var v struct {
+		EventId  string `json:"event_id"`
+		PkgId    string `json:"pkg_id"`
+		PRNumber int    `json:"number"`
+	}
+
+	if err := json.Unmarshal(data, &v); err != nil {
+		return ""
+	}
+
+	if v.EventId != "" {
+		return v.EventId
+	}
+
+	// it is in the same form as the event id.
+	if v.PkgId != "" && v.PRNumber > 0 {
+		key := fmt.Sprintf("%s/%s/%d", topic, v.PkgId, v.PRNumber)
+
+		return uuid.NewSHA1(uuid.NameSpaceOID, []byte(key)).String()
+	}
+
+	return ""
+}
@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestEventIdOf(t *testing.T) {
+	ciResult := `{"pkg_id":"p1","number":3,"success":true,"detail":"a"}`
+
+	cases := []struct {
+		name  string
+		topic string
+		data  string
+		want  string
+	}{
+		{"event id", "t", `{"event_id":"e1","pkg_id":"p1","number":3}`, "e1"},
+		{"no pr number", "t", `{"pkg_id":"p1"}`, ""},
+		{"no pkg id", "t", `{"number":3}`, ""},
+		{"invalid json", "t", `not json`, ""},
+		{"ci result", "t", ciResult, eventIdOf("t", []byte(`{"pkg_id":"p1","number":3,"detail":"b"}`))},
+	}
+
+	for _, c := range cases {
+		if got := eventIdOf(c.topic, []byte(c.data)); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+
+	if eventIdOf("t", []byte(ciResult)) == "" {
+		t.Error("expect the event id of the ci result")
+	}
+
+	if eventIdOf("t", []byte(ciResult)) == eventIdOf("t2", []byte(ciResult)) {
+		t.Error("the event ids of different topics should be different")
+	}
+
+	if eventIdOf("t", []byte(ciResult)) == eventIdOf("t", []byte(`{"pkg_id":"p1","number":4}`)) {
+		t.Error("the event ids of different prs should be different")
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// poisonError is the error of a message which can never be handled,
+// such as the one that can't be parsed. It will not be retried.
+type poisonError struct {
+	err error
+}
+
+func newPoisonError(err error) poisonError {
+	return poisonError{err}
+}
+
+func (e poisonError) Error() string {
+	return "poison message, " + e.err.Error()
+}
+
+func (e poisonError) Unwrap() error {
+	return e.err
+}
+
+func isPoisonError(err error) bool {
+	return errors.As(err, new(poisonError))
+}
+
+// retryPolicy
+type retryPolicy struct {
+	// MaxAttempts is the max times to handle a message, including the first one.
+	MaxAttempts int `json:"max_attempts"`
+
+	// InitialInterval is the interval in milliseconds before the first retry.
+	// It will be doubled for each retry up to MaxInterval.
+	InitialInterval int `json:"initial_interval"`
+
+	// MaxInterval is the max interval in milliseconds between two retries.
+	MaxInterval int `json:"max_interval"`
+}
+
+func (p *retryPolicy) setDefault(d *retryPolicy) {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = d.MaxAttempts
+	}
+
+	if p.InitialInterval <= 0 {
+		p.InitialInterval = d.InitialInterval
+	}
+
+	if p.MaxInterval < p.InitialInterval {
+		p.MaxInterval = d.MaxInterval
+	}
+
+	if p.MaxInterval < p.InitialInterval {
+		p.MaxInterval = p.InitialInterval
+	}
+}
+
+// run calls f until it succeeds, returns a poison error or the attempts run out.
+// It returns the times that f was called and the last error.
+func (p *retryPolicy) run(f func() error) (attempts int, err error) {
+	interval := time.Duration(p.InitialInterval) * time.Millisecond
+	max := time.Duration(p.MaxInterval) * time.Millisecond
+
+	for {
+		attempts++
+
+		if err = f(); err == nil || isPoisonError(err) || attempts >= p.MaxAttempts {
+			return
+		}
+
+		time.Sleep(interval)
+
+		if interval *= 2; interval > max {
+			interval = max
+		}
+	}
+}
+
+// topicRetryPolicy
+type topicRetryPolicy struct {
+	Topic string `json:"topic" required:"true"`
+
+	retryPolicy
+}
+
+// retryConfig
+type retryConfig struct {
+	Default retryPolicy        `json:"default"`
+	Topics  []topicRetryPolicy `json:"topics"`
+}
+
+func (cfg *retryConfig) SetDefault() {
+	cfg.Default.setDefault(&retryPolicy{
+		MaxAttempts:     3,
+		InitialInterval: 500,
+		MaxInterval:     10000,
+	})
+
+	for i := range cfg.Topics {
+		cfg.Topics[i].setDefault(&cfg.Default)
+	}
+}
+
+func (cfg *retryConfig) Validate() error {
+	topics := map[string]bool{}
+
+	for i := range cfg.Topics {
+		t := cfg.Topics[i].Topic
+
+		if t == "" || topics[t] {
+			return fmt.Errorf("invalid or duplicate topic: %s of retry policy", t)
+		}
+
+		topics[t] = true
+	}
+
+	return nil
+}
+
+func (cfg *retryConfig) policy(topic string) *retryPolicy {
+	for i := range cfg.Topics {
+		if cfg.Topics[i].Topic == topic {
+			return &cfg.Topics[i].retryPolicy
+		}
+	}
+
+	return &cfg.Default
+}
@@ -4,12 +4,19 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/sirupsen/logrus"
+
 	"github.com/opensourceways/software-package-server/common/infrastructure/kafka"
 	"github.com/opensourceways/software-package-server/softwarepkg/app"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/utils"
 )
 
 type server struct {
-	service app.SoftwarePkgMessageService
+	service     app.SoftwarePkgMessageService
+	outbox      app.SoftwarePkgOutboxService
+	deadLetters deadLetterService
+	processed   processedEvents
 }
 
 func (s *server) run(ctx context.Context, cfg *Config) error {
@@ -17,12 +24,17 @@ func (s *server) run(ctx context.Context, cfg *Config) error {
 		return err
 	}
 
-	<-ctx.Done()
+	go utils.RunWithBackoff(ctx, &cfg.OutboxRelay, s.outbox.PublishPendingEvents)
+
+	checker := ciTimeoutChecker{s.service}
+	checker.run(ctx, &cfg.CITimeoutChecker)
 
 	return nil
 }
 
-func (s *server) subscribe(cfg *Config) error {
+// handlers returns the handlers of the subscribed topics.
+// The message which was processed before will be skipped.
+func (s *server) handlers(cfg *Config) map[string]kafka.Handler {
 	topics := &cfg.Topics
 
 	h := map[string]kafka.Handler{
@@ -34,13 +46,54 @@ func (s *server) subscribe(cfg *Config) error {
 		topics.SoftwarePkgAlreadyExisted: s.handlePkgAlreadyExisted,
 	}
 
+	for topic, f := range h {
+		h[topic] = s.processed.idempotent(topic, f)
+	}
+
+	return h
+}
+
+func (s *server) subscribe(cfg *Config) error {
+	h := s.handlers(cfg)
+
+	for topic, f := range h {
+		h[topic] = s.withRetry(topic, f, cfg)
+	}
+
+	h[cfg.DeadLetter.Topic] = s.deadLetters.save
+
 	return kafka.Subscriber().Subscribe(cfg.GroupName, h)
 }
 
+// withRetry retries the handler by the retry policy of the topic, and
+// sends the message to the dead letter topic if it still fails.
+// The message which is skipped by the domain is acked without retry.
+func (s *server) withRetry(topic string, h kafka.Handler, cfg *Config) kafka.Handler {
+	policy := cfg.Retry.policy(topic)
+
+	return func(data []byte) error {
+		attempts, err := policy.run(func() error {
+			err := h(data)
+			if err != nil && domain.IsErrorSkipped(err) {
+				logrus.Infof("skip the message of topic:%s, %s", topic, err.Error())
+
+				return nil
+			}
+
+			return err
+		})
+		if err != nil {
+			s.deadLetters.send(topic, data, err, attempts)
+		}
+
+		return err
+	}
+}
+
 func (s *server) handlePkgCIChecking(data []byte) error {
 	cmd, err := cmdToHandlePkgCIChecking(data)
 	if err != nil {
-		return err
+		return newPoisonError(err)
 	}
 
 	return s.service.HandlePkgCIChecking(cmd)
@@ -50,7 +103,7 @@ func (s *server) handlePkgCIChecked(data []byte) error {
 	msg := new(msgToHandlePkgCIChecked)
 
 	if err := json.Unmarshal(data, msg); err != nil {
-		return err
+		return newPoisonError(err)
 	}
 
 	return s.service.HandlePkgCIChecked(msg.toCmd())
@@ -60,12 +113,12 @@ func (s *server) handlePkgInitialized(data []byte) error {
 	msg := new(msgToHandlePkgInitialized)
 
 	if err := json.Unmarshal(data, msg); err != nil {
-		return err
+		return newPoisonError(err)
 	}
 
 	cmd, err := msg.toCmd()
 	if err != nil {
-		return err
+		return newPoisonError(err)
 	}
 
 	return s.service.HandlePkgInitialized(cmd)
@@ -75,12 +128,12 @@ func (s *server) handlePkgRepoCreated(data []byte) error {
 	msg := new(msgToHandlePkgRepoCreated)
 
 	if err := json.Unmarshal(data, msg); err != nil {
-		return err
+		return newPoisonError(err)
 	}
 
 	cmd, err := msg.toCmd()
 	if err != nil {
-		return err
+		return newPoisonError(err)
 	}
 
 	return s.service.HandlePkgRepoCreated(cmd)
@@ -90,12 +143,12 @@ func (s *server) handlePkgCodeSaved(data []byte) error {
 	msg := new(msgToHandlePkgCodeSaved)
 
 	if err := json.Unmarshal(data, msg); err != nil {
-		return err
+		return newPoisonError(err)
 	}
 
 	cmd, err := msg.toCmd()
 	if err != nil {
-		return err
+		return newPoisonError(err)
 	}
 
 	return s.service.HandlePkgCodeSaved(cmd)
@@ -104,7 +157,7 @@ func (s *server) handlePkgCodeSaved(data []byte) error {
 func (s *server) handlePkgAlreadyExisted(data []byte) error {
 	cmd, err := cmdToHandlePkgAlreadyExisted(data)
 	if err != nil {
-		return err
+		return newPoisonError(err)
 	}
 
 	return s.service.HandlePkgAlreadyExisted(cmd)
@@ -22,6 +22,7 @@ import (
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/repositoryimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/sensitivewordsimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/sigvalidatorimpl"
+	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/specparserimpl"
 	"github.com/opensourceways/software-package-server/softwarepkg/infrastructure/translationimpl"
 )
 
@@ -74,6 +75,9 @@ func initSoftwarePkgService(v1 *gin.RouterGroup, cfg *config.Config) {
 			sensitivewordsimpl.Sensitive(),
 			maintainerimpl.Maintainer(),
 			translationimpl.Translation(),
+			specparserimpl.SpecParser(),
+			clavalidatorimpl.Instance(),
+			&cfg.CLAChecking,
 		),
 	)
 }
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/clavalidator"
+	"github.com/opensourceways/software-package-server/utils"
+)
+
+var errorCLANotSigned = errors.New("the cla is not signed")
+
+func newCLAChecker(cfg *CLAConfig, cla clavalidator.ClaValidator) *claChecker {
+	exemptions := make(map[string]bool, len(cfg.Exemptions))
+	for _, v := range cfg.Exemptions {
+		exemptions[v] = true
+	}
+
+	return &claChecker{
+		cla:        cla,
+		enabled:    cfg.Enabled,
+		duration:   cfg.CacheDuration,
+		exemptions: exemptions,
+		signed:     map[string]int64{},
+	}
+}
+
+// claChecker checks whether the user has signed the cla. Only the signed
+// ones are cached, so the user can go on right after signing it.
+type claChecker struct {
+	cla        clavalidator.ClaValidator
+	enabled    bool
+	duration   int64
+	exemptions map[string]bool
+
+	lock sync.Mutex
+	// signed is the expiry of the cached result of the email.
+	signed map[string]int64
+}
+
+// check returns errorSoftwarePkgCLANotSigned if the user has not signed the cla.
+func (c *claChecker) check(user *domain.User) (string, error) {
+	if !c.enabled || (user.Account != nil && c.exemptions[user.Account.Account()]) {
+		return "", nil
+	}
+
+	if user.Email == nil {
+		return errorSoftwarePkgCLANotSigned, errorCLANotSigned
+	}
+
+	email := user.Email.Email()
+	now := utils.Now()
+
+	if c.isCached(email, now) {
+		return "", nil
+	}
+
+	signed, err := c.cla.HasSignedCLA(user.Email)
+	if err != nil {
+		return "", err
+	}
+
+	if !signed {
+		return errorSoftwarePkgCLANotSigned, errorCLANotSigned
+	}
+
+	c.cache(email, now)
+
+	return "", nil
+}
+
+func (c *claChecker) isCached(email string, now int64) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	expiry, ok := c.signed[email]
+	if ok && expiry <= now {
+		delete(c.signed, email)
+
+		return false
+	}
+
+	return ok
+}
+
+func (c *claChecker) cache(email string, now int64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.signed[email] = now + c.duration
+}
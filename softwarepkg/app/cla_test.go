@@ -0,0 +1,96 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+	"github.com/opensourceways/software-package-server/utils"
+)
+
+type claValidatorMock struct {
+	signed bool
+	err    error
+	times  int
+}
+
+func (m *claValidatorMock) HasSignedCLA(dp.Email) (bool, error) {
+	m.times++
+
+	return m.signed, m.err
+}
+
+func TestCLAChecker(t *testing.T) {
+	account, _ := dp.NewAccount("robot")
+	email, _ := dp.NewEmail("foo@example.com")
+
+	signer := &domain.User{Account: account, Email: email}
+	noEmail := &domain.User{Account: account}
+
+	now := utils.Now()
+	errValidator := errors.New("validator")
+
+	cases := []struct {
+		name       string
+		disabled   bool
+		exemptions []string
+		user       *domain.User
+		cla        claValidatorMock
+		// expiry is the expiry of the cached result, 0 means not cached.
+		expiry int64
+		code   string
+		err    error
+		times  int
+		cached bool
+	}{
+		{"disabled", true, nil, noEmail, claValidatorMock{}, 0, "", nil, 0, false},
+		{"exempted", false, []string{"robot"}, noEmail, claValidatorMock{}, 0, "", nil, 0, false},
+		{
+			"without email", false, nil, noEmail, claValidatorMock{signed: true}, 0,
+			errorSoftwarePkgCLANotSigned, errorCLANotSigned, 0, false,
+		},
+		{"signed", false, nil, signer, claValidatorMock{signed: true}, 0, "", nil, 1, true},
+		{
+			"not signed", false, nil, signer, claValidatorMock{}, 0,
+			errorSoftwarePkgCLANotSigned, errorCLANotSigned, 1, false,
+		},
+		{
+			"failed to validate", false, nil, signer, claValidatorMock{err: errValidator}, 0,
+			"", errValidator, 1, false,
+		},
+		{"cached", false, nil, signer, claValidatorMock{}, now + 100, "", nil, 0, true},
+		{"cache expired", false, nil, signer, claValidatorMock{signed: true}, now - 1, "", nil, 1, true},
+		{
+			"cache expired and not signed", false, nil, signer, claValidatorMock{}, now - 1,
+			errorSoftwarePkgCLANotSigned, errorCLANotSigned, 1, false,
+		},
+	}
+
+	for i := range cases {
+		c := &cases[i]
+
+		checker := newCLAChecker(
+			&CLAConfig{Enabled: !c.disabled, CacheDuration: 100, Exemptions: c.exemptions},
+			&c.cla,
+		)
+
+		if c.expiry != 0 {
+			checker.signed[email.Email()] = c.expiry
+		}
+
+		code, err := checker.check(c.user)
+
+		if code != c.code || err != c.err {
+			t.Errorf("%s: got (%q, %v), want (%q, %v)", c.name, code, err, c.code, c.err)
+		}
+
+		if c.cla.times != c.times {
+			t.Errorf("%s: got %d calls of validator, want %d", c.name, c.cla.times, c.times)
+		}
+
+		if _, ok := checker.signed[email.Email()]; ok != c.cached {
+			t.Errorf("%s: got cached %v, want %v", c.name, ok, c.cached)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package app
+
+// CLAConfig is the config of checking whether the user has signed the cla
+// when applying a pkg and reviewing it.
+type CLAConfig struct {
+	// Enabled is false by default, so that the cla is not checked.
+	Enabled bool `json:"enabled"`
+
+	// CacheDuration is the duration in seconds within which the user who
+	// has signed the cla will not be checked again.
+	CacheDuration int64 `json:"cache_duration"`
+
+	// Exemptions are the accounts which needn't sign the cla, such as the robots.
+	Exemptions []string `json:"exemptions"`
+}
+
+func (cfg *CLAConfig) SetDefault() {
+	if cfg.CacheDuration <= 0 {
+		cfg.CacheDuration = 300
+	}
+}
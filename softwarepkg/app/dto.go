@@ -13,6 +13,47 @@ type CmdToApplyNewSoftwarePkg struct {
 	Application domain.SoftwarePkgApplication
 }
 
+// The fields of the application to apply a new pkg, besides the ones
+// of domain.SoftwarePkgApplication.
+const (
+	FieldPkgName  = "pkg_name"
+	FieldImporter = "importer"
+)
+
+// CmdToValidateNewSoftwarePkg is the application to be validated. The fields
+// which are missing or invalid are left nil, and their problems are added.
+type CmdToValidateNewSoftwarePkg struct {
+	CmdToApplyNewSoftwarePkg
+
+	Problems []ApplicationProblemDTO
+}
+
+// AddProblem adds the problem of the field. The code is the error code
+// which would be returned when applying.
+func (cmd *CmdToValidateNewSoftwarePkg) AddProblem(field, code, msg string) {
+	cmd.Problems = append(cmd.Problems, ApplicationProblemDTO{
+		Field: field,
+		Code:  code,
+		Msg:   msg,
+	})
+}
+
+// ApplicationProblemDTO
+type ApplicationProblemDTO struct {
+	Field string `json:"field"`
+	Code  string `json:"code"`
+	Msg   string `json:"msg"`
+
+	// Segments are the sensitive parts of the content if it is sensitive.
+	Segments []string `json:"segments,omitempty"`
+}
+
+// ApplicationValidationDTO
+type ApplicationValidationDTO struct {
+	Valid    bool                    `json:"valid"`
+	Problems []ApplicationProblemDTO `json:"problems"`
+}
+
 type CmdToUpdateSoftwarePkgApplication struct {
 	PkgId string
 	CmdToApplyNewSoftwarePkg
@@ -21,12 +62,16 @@ type CmdToUpdateSoftwarePkgApplication struct {
 type CmdToListPkgs = repository.OptToFindSoftwarePkgs
 
 type CmdToWriteSoftwarePkgReviewComment struct {
-	Author  dp.Account
+	Author  domain.User
 	Content dp.ReviewComment
 }
 
 type NewSoftwarePkgDTO struct {
 	Id string `json:"id"`
+
+	// Warning is set if there are pkgs which may be the same software.
+	Warning     string                  `json:"warning,omitempty"`
+	SimilarPkgs []SimilarSoftwarePkgDTO `json:"similar_pkgs,omitempty"`
 }
 
 // SoftwarePkgBasicInfoDTO
@@ -41,6 +86,10 @@ type SoftwarePkgBasicInfoDTO struct {
 	PkgDesc   string `json:"desc"`
 	Sig       string `json:"sig"`
 	Platform  string `json:"platform"`
+	Revision  int    `json:"revision"`
+
+	// AllowedActions are the actions which can be done in current phase.
+	AllowedActions []string `json:"allowed_actions"`
 }
 
 func toSoftwarePkgBasicInfoDTO(v *domain.SoftwarePkgBasicInfo) SoftwarePkgBasicInfoDTO {
@@ -55,6 +104,9 @@ func toSoftwarePkgBasicInfoDTO(v *domain.SoftwarePkgBasicInfo) SoftwarePkgBasicI
 		Platform:  app.PackagePlatform.PackagePlatform(),
 		Importer:  v.Importer.Account.Account(),
 		AppliedAt: utils.ToDate(v.AppliedAt),
+		Revision:  v.Revision,
+
+		AllowedActions: v.AllowedActions(),
 	}
 
 	if v.RepoLink != nil {
@@ -84,10 +136,11 @@ type SoftwarePkgApplicationDTO struct {
 	PackagePlatform   string `json:"platform"`
 	ImportingPkgSig   string `json:"sig"`
 	ReasonToImportPkg string `json:"reason"`
+	License           string `json:"license"`
 }
 
 func toSoftwarePkgApplicationDTO(v *domain.SoftwarePkgApplication) SoftwarePkgApplicationDTO {
-	return SoftwarePkgApplicationDTO{
+	dto := SoftwarePkgApplicationDTO{
 		SpecURL:           v.SourceCode.SpecURL.URL(),
 		Upstream:          v.SourceCode.Upstream.URL(),
 		SrcRPMURL:         v.SourceCode.SrcRPMURL.URL(),
@@ -96,6 +149,198 @@ func toSoftwarePkgApplicationDTO(v *domain.SoftwarePkgApplication) SoftwarePkgAp
 		ImportingPkgSig:   v.ImportingPkgSig.ImportingPkgSig(),
 		ReasonToImportPkg: v.ReasonToImportPkg.ReasonToImportPkg(),
 	}
+
+	if v.License != nil {
+		dto.License = v.License.License()
+	}
+
+	return dto
+}
+
+// LicenseCheckDTO
+type LicenseCheckDTO struct {
+	CheckItem int    `json:"check_item"`
+	License   string `json:"license"`
+	Compliant bool   `json:"compliant"`
+	Reason    string `json:"reason"`
+}
+
+func toLicenseCheckDTO(v *domain.LicenseCheck) *LicenseCheckDTO {
+	if v == nil {
+		return nil
+	}
+
+	return &LicenseCheckDTO{
+		CheckItem: v.Index,
+		License:   v.License,
+		Compliant: v.Compliant,
+		Reason:    v.Reason,
+	}
+}
+
+// SoftwarePkgSpecDTO
+type SoftwarePkgSpecDTO struct {
+	Name          string   `json:"name"`
+	Version       string   `json:"version"`
+	License       string   `json:"license"`
+	BuildRequires []string `json:"build_requires"`
+	Requires      []string `json:"requires"`
+	Sources       []string `json:"sources"`
+	Patches       []string `json:"patches"`
+	Files         []string `json:"files"`
+}
+
+func toSoftwarePkgSpecDTO(v *domain.SoftwarePkgSpec) SoftwarePkgSpecDTO {
+	return SoftwarePkgSpecDTO{
+		Name:          v.Name,
+		Version:       v.Version,
+		License:       v.License,
+		BuildRequires: v.BuildRequires,
+		Requires:      v.Requires,
+		Sources:       v.Sources,
+		Patches:       v.Patches,
+		Files:         v.Files,
+	}
+}
+
+// SoftwarePkgDependencyDTO
+type SoftwarePkgDependencyDTO struct {
+	Kind        string `json:"kind"`
+	Requirement string `json:"requirement"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	PkgId       string `json:"pkg_id"`
+}
+
+// SoftwarePkgDependenciesDTO
+type SoftwarePkgDependenciesDTO struct {
+	Dependencies []SoftwarePkgDependencyDTO `json:"dependencies"`
+
+	// Missing is the number of dependencies which are not available in the
+	// distribution yet, including the ones whose applications are in progress.
+	Missing int `json:"missing"`
+}
+
+func toSoftwarePkgDependenciesDTO(v []domain.SoftwarePkgDependency) SoftwarePkgDependenciesDTO {
+	dto := SoftwarePkgDependenciesDTO{
+		Dependencies: make([]SoftwarePkgDependencyDTO, len(v)),
+	}
+
+	for i := range v {
+		item := &v[i]
+
+		d := SoftwarePkgDependencyDTO{
+			Kind:        item.Kind,
+			Requirement: item.Requirement,
+			Status:      item.Status,
+			PkgId:       item.PkgId,
+		}
+
+		if item.Name != nil {
+			d.Name = item.Name.PackageName()
+		}
+
+		if item.IsMissing() {
+			dto.Missing++
+		}
+
+		dto.Dependencies[i] = d
+	}
+
+	return dto
+}
+
+// CmdToListSimilarPkgs
+type CmdToListSimilarPkgs struct {
+	PkgName  dp.PackageName
+	Upstream dp.URL
+}
+
+// SimilarSoftwarePkgDTO
+type SimilarSoftwarePkgDTO struct {
+	Id       string `json:"id"`
+	PkgName  string `json:"pkg_name"`
+	Phase    string `json:"phase"`
+	Upstream string `json:"upstream"`
+	Reason   string `json:"reason"`
+	Distance int    `json:"distance"`
+}
+
+func toSimilarSoftwarePkgDTOs(v []domain.SimilarSoftwarePkg) []SimilarSoftwarePkgDTO {
+	r := make([]SimilarSoftwarePkgDTO, len(v))
+
+	for i := range v {
+		item := &v[i]
+
+		r[i] = SimilarSoftwarePkgDTO{
+			Id:       item.Id,
+			PkgName:  item.PkgName.PackageName(),
+			Phase:    item.Phase.PackagePhase(),
+			Reason:   item.Reason,
+			Distance: item.Distance,
+		}
+
+		if item.Upstream != nil {
+			r[i].Upstream = item.Upstream.URL()
+		}
+	}
+
+	return r
+}
+
+// SoftwarePkgRevisionDTO
+type SoftwarePkgRevisionDTO struct {
+	Number      int                       `json:"number"`
+	Author      string                    `json:"author"`
+	CreatedAt   string                    `json:"created_at"`
+	Application SoftwarePkgApplicationDTO `json:"application"`
+}
+
+func toSoftwarePkgRevisionDTOs(v []domain.SoftwarePkgRevision) (r []SoftwarePkgRevisionDTO) {
+	if n := len(v); n > 0 {
+		r = make([]SoftwarePkgRevisionDTO, n)
+		for i := range v {
+			item := &v[i]
+
+			r[i] = SoftwarePkgRevisionDTO{
+				Number:      item.Number,
+				Author:      item.Author.Account(),
+				CreatedAt:   utils.ToDateTime(item.CreatedAt),
+				Application: toSoftwarePkgApplicationDTO(&item.Application),
+			}
+		}
+	}
+
+	return
+}
+
+// CmdToDiffRevisions
+type CmdToDiffRevisions struct {
+	PkgId string
+	From  int
+	To    int
+}
+
+// ApplicationFieldDiffDTO
+type ApplicationFieldDiffDTO struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+func toApplicationFieldDiffDTOs(v []domain.ApplicationFieldDiff) (r []ApplicationFieldDiffDTO) {
+	if n := len(v); n > 0 {
+		r = make([]ApplicationFieldDiffDTO, n)
+		for i := range v {
+			r[i] = ApplicationFieldDiffDTO{
+				Field: v[i].Field,
+				Old:   v[i].Old,
+				New:   v[i].New,
+			}
+		}
+	}
+
+	return
 }
 
 // SoftwarePkgReviewCommentDTO
@@ -130,17 +375,33 @@ func toSoftwarePkgReviewCommentDTOs(v []domain.SoftwarePkgReviewComment) (r []So
 
 // SoftwarePkgOperationLogDTO
 type SoftwarePkgOperationLogDTO struct {
-	User   string `json:"user"`
-	Time   string `json:"time"`
-	Action string `json:"action"`
+	User     string `json:"user"`
+	Time     string `json:"time"`
+	Action   string `json:"action"`
+	OldPhase string `json:"old_phase,omitempty"`
+	NewPhase string `json:"new_phase,omitempty"`
+	CIPRNum  int    `json:"ci_pr_num,omitempty"`
+	Reason   string `json:"reason,omitempty"`
 }
 
 func toSoftwarePkgOperationLogDTO(v *domain.SoftwarePkgOperationLog) SoftwarePkgOperationLogDTO {
-	return SoftwarePkgOperationLogDTO{
-		User:   v.User.Account(),
-		Time:   utils.ToDateTime(v.Time),
-		Action: v.Action.PackageOperationLogAction(),
+	dto := SoftwarePkgOperationLogDTO{
+		User:    v.User.Account(),
+		Time:    utils.ToDateTime(v.Time),
+		Action:  v.Action.PackageOperationLogAction(),
+		CIPRNum: v.Details.CIPRNum,
+		Reason:  v.Details.Reason,
+	}
+
+	if p := v.Details.OldPhase; p != nil {
+		dto.OldPhase = p.PackagePhase()
+	}
+
+	if p := v.Details.NewPhase; p != nil {
+		dto.NewPhase = p.PackagePhase()
 	}
+
+	return dto
 }
 
 func toSoftwarePkgOperationLogDTOs(v []domain.SoftwarePkgOperationLog) (r []SoftwarePkgOperationLogDTO) {
@@ -160,25 +421,79 @@ type SoftwarePkgApproverDTO struct {
 	IsTC    bool   `json:"is_tc"`
 }
 
+// ReviewQuorumDTO
+type ReviewQuorumDTO struct {
+	Reached                       bool     `json:"reached"`
+	VetoedByTC                    []string `json:"vetoed_by_tc"`
+	ApprovedByTC                  []string `json:"approved_by_tc"`
+	ApprovedBySigMaintainer       []string `json:"approved_by_sig_maintainer"`
+	MinNumApprovedByTC            int      `json:"min_num_approved_by_tc"`
+	MinNumApprovedBySigMaintainer int      `json:"min_num_approved_by_sig_maintainer"`
+}
+
+func toReviewQuorumDTO(v *domain.ReviewQuorum) ReviewQuorumDTO {
+	return ReviewQuorumDTO{
+		Reached:                       v.IsReached(),
+		VetoedByTC:                    toAccounts(v.VetoedByTC),
+		ApprovedByTC:                  toAccounts(v.ApprovedByTC),
+		ApprovedBySigMaintainer:       toAccounts(v.ApprovedBySigMaintainer),
+		MinNumApprovedByTC:            v.MinNumApprovedByTC,
+		MinNumApprovedBySigMaintainer: v.MinNumApprovedBySigMaintainer,
+	}
+}
+
+func toCommunityRoles(v []dp.CommunityRole) (r []string) {
+	if n := len(v); n > 0 {
+		r = make([]string, n)
+		for i := range v {
+			r[i] = v[i].CommunityRole()
+		}
+	}
+
+	return
+}
+
+func toAccounts(v []dp.Account) (r []string) {
+	if n := len(v); n > 0 {
+		r = make([]string, n)
+		for i := range v {
+			r[i] = v[i].Account()
+		}
+	}
+
+	return
+}
+
 // SoftwarePkgReviewDTO
 type SoftwarePkgReviewDTO struct {
 	SoftwarePkgBasicInfoDTO
 
 	Logs        []SoftwarePkgOperationLogDTO  `json:"logs"`
+	Quorum      ReviewQuorumDTO               `json:"quorum"`
 	Comments    []SoftwarePkgReviewCommentDTO `json:"comments"`
 	ApprovedBy  []SoftwarePkgApproverDTO      `json:"approved_by"`
 	RejectedBy  []SoftwarePkgApproverDTO      `json:"rejected_by"`
 	Application SoftwarePkgApplicationDTO     `json:"application"`
+	Spec        SoftwarePkgSpecDTO            `json:"spec"`
+
+	LicenseCheck *LicenseCheckDTO `json:"license_check,omitempty"`
+	SrcRPMNotes  []string         `json:"src_rpm_notes,omitempty"`
 }
 
 func toSoftwarePkgReviewDTO(v *domain.SoftwarePkg) SoftwarePkgReviewDTO {
+	quorum := v.Review.Quorum()
+
 	return SoftwarePkgReviewDTO{
 		SoftwarePkgBasicInfoDTO: toSoftwarePkgBasicInfoDTO(&v.SoftwarePkgBasicInfo),
 		Logs:                    toSoftwarePkgOperationLogDTOs(v.Logs),
+		Quorum:                  toReviewQuorumDTO(&quorum),
 		Comments:                toSoftwarePkgReviewCommentDTOs(v.Comments),
 		ApprovedBy:              toSoftwarePkgApproverDTO(v.ApprovedBy),
 		RejectedBy:              toSoftwarePkgApproverDTO(v.RejectedBy),
 		Application:             toSoftwarePkgApplicationDTO(&v.Application),
+		Spec:                    toSoftwarePkgSpecDTO(&v.Spec),
+		LicenseCheck:            toLicenseCheckDTO(v.Review.LicenseCheck),
+		SrcRPMNotes:             v.SrcRPMNotes,
 	}
 }
 
@@ -209,6 +524,105 @@ func toSoftwarePkgsDTO(v []domain.SoftwarePkgBasicInfo, total int) SoftwarePkgsD
 	}
 }
 
+// CmdToListCheckItems
+type CmdToListCheckItems struct {
+	Sig      dp.ImportingPkgSig
+	Platform dp.PackagePlatform
+}
+
+// CheckItemDTO
+type CheckItemDTO struct {
+	Index  int      `json:"index"`
+	Item   string   `json:"item"`
+	Desc   string   `json:"desc"`
+	Owners []string `json:"owners"`
+}
+
+func toCheckItemDTOs(v []domain.CheckItem) (r []CheckItemDTO) {
+	if n := len(v); n > 0 {
+		r = make([]CheckItemDTO, n)
+		for i := range v {
+			item := &v[i]
+
+			r[i] = CheckItemDTO{
+				Index:  item.Index,
+				Item:   item.Item,
+				Desc:   item.Desc,
+				Owners: toCommunityRoles(item.Owners),
+			}
+		}
+	}
+
+	return
+}
+
+// CheckItemReviewDTO
+type CheckItemReviewDTO struct {
+	Index   int                      `json:"index"`
+	Item    string                   `json:"item"`
+	Desc    string                   `json:"desc"`
+	Result  string                   `json:"result"`
+	Reviews []CheckItemReviewInfoDTO `json:"reviews"`
+}
+
+// CheckItemReviewInfoDTO
+type CheckItemReviewInfoDTO struct {
+	Account string   `json:"account"`
+	Roles   []string `json:"roles"`
+	Pass    bool     `json:"pass"`
+	Desc    string   `json:"desc"`
+	Stale   bool     `json:"stale"`
+
+	// Revision is the revision of application which the review was made against.
+	Revision int `json:"revision"`
+
+	// Outdated means the review was made against an older revision.
+	Outdated bool `json:"outdated"`
+}
+
+func toCheckItemReviewDTO(v *domain.CheckItemReviewInfos, revision int) CheckItemReviewDTO {
+	dto := CheckItemReviewDTO{
+		Index:  v.Item.Index,
+		Item:   v.Item.Item,
+		Desc:   v.Item.Desc,
+		Result: v.Result().CheckItemResult(),
+	}
+
+	if n := len(v.Infos); n > 0 {
+		dto.Reviews = make([]CheckItemReviewInfoDTO, n)
+
+		for i := range v.Infos {
+			info := &v.Infos[i]
+
+			dto.Reviews[i] = CheckItemReviewInfoDTO{
+				Account: info.User.Account(),
+				Roles:   toCommunityRoles(info.Role),
+				Pass:    info.Pass,
+				Desc:    info.Desc,
+				Stale:   info.Stale,
+
+				Revision: info.Revision,
+				Outdated: info.Revision < revision,
+			}
+		}
+	}
+
+	return dto
+}
+
+func toCheckItemReviewDTOs(v *domain.SoftwarePkgReview, revision int) (r []CheckItemReviewDTO) {
+	if n := len(v.Items); n > 0 {
+		r = make([]CheckItemReviewDTO, n)
+		for i := range v.Items {
+			info := v.CheckItemReview(&v.Items[i])
+
+			r[i] = toCheckItemReviewDTO(&info, revision)
+		}
+	}
+
+	return
+}
+
 // CmdToTranslateReviewComment
 type CmdToTranslateReviewComment = repository.TranslatedReviewCommentIndex
 
@@ -2,6 +2,10 @@ package app
 
 import commonrepo "github.com/opensourceways/software-package-server/common/domain/repository"
 
+// maxRetryTimesOnConflict is the max times to retry when the pkg was
+// updated concurrently by others.
+const maxRetryTimesOnConflict = 3
+
 const (
 	errorSoftwarePkgExists          = "software_pkg_exists"
 	errorSoftwarePkgNotFound        = "software_pkg_not_found"
@@ -9,6 +13,12 @@ const (
 	errorSoftwarePkgCannotComment   = "software_pkg_cannot_comment"
 	errorSoftwarePkgCommentIllegal  = "software_pkg_comment_illegal"
 	errorSoftwarePkgCommentNotFound = "software_pkg_comment_not_found"
+	errorSoftwarePkgInvalidCommand  = "software_pkg_invalid_command"
+	errorSoftwarePkgConflict        = "software_pkg_conflict"
+	errorSoftwarePkgInvalidSpec     = "software_pkg_invalid_spec"
+	errorSoftwarePkgCLANotSigned    = "software_pkg_cla_not_signed"
+
+	errorSoftwarePkgRevisionNotFound = "software_pkg_revision_not_found"
 )
 
 func errorCodeForFindingPkg(err error) string {
@@ -18,3 +28,17 @@ func errorCodeForFindingPkg(err error) string {
 
 	return ""
 }
+
+// retryOnConflict calls f again when it fails for the concurrent updating.
+// f should reload the pkg and reapply the domain operation each time.
+func retryOnConflict(f func() (string, error)) (code string, err error) {
+	for i := 0; i < maxRetryTimesOnConflict; i++ {
+		if code, err = f(); err == nil || !commonrepo.IsErrorConcurrentUpdating(err) {
+			return
+		}
+	}
+
+	code = errorSoftwarePkgConflict
+
+	return
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	commonrepo "github.com/opensourceways/software-package-server/common/domain/repository"
+)
+
+func TestRetryOnConflict(t *testing.T) {
+	conflict := commonrepo.NewErrorConcurrentUpdating(errors.New("conflict"))
+	other := errors.New("other")
+
+	cases := []struct {
+		name  string
+		errs  []error
+		times int
+		code  string
+		err   error
+	}{
+		{"succeed at once", []error{nil}, 1, "", nil},
+		{"succeed after conflict", []error{conflict, nil}, 2, "", nil},
+		{"stop at other error", []error{conflict, other}, 2, "", other},
+		{
+			"exhausted", []error{conflict, conflict, conflict},
+			maxRetryTimesOnConflict, errorSoftwarePkgConflict, conflict,
+		},
+	}
+
+	for _, c := range cases {
+		times := 0
+
+		code, err := retryOnConflict(func() (string, error) {
+			err := c.errs[times]
+			times++
+
+			return "", err
+		})
+
+		if times != c.times {
+			t.Errorf("%s: got %d calls, want %d", c.name, times, c.times)
+		}
+
+		if code != c.code {
+			t.Errorf("%s: got code %q, want %q", c.name, code, c.code)
+		}
+
+		if err != c.err {
+			t.Errorf("%s: got err %v, want %v", c.name, err, c.err)
+		}
+	}
+}
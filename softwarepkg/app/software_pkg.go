@@ -7,6 +7,7 @@ import (
 
 	commonrepo "github.com/opensourceways/software-package-server/common/domain/repository"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/clavalidator"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/maintainer"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/message"
@@ -14,19 +15,31 @@ import (
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/repository"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/sensitivewords"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/service"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/specparser"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/translation"
 )
 
 type SoftwarePkgService interface {
 	ApplyNewPkg(*CmdToApplyNewSoftwarePkg) (NewSoftwarePkgDTO, string, error)
+	ValidateNewPkg(*CmdToValidateNewSoftwarePkg) (ApplicationValidationDTO, error)
 	GetPkgReviewDetail(string) (SoftwarePkgReviewDTO, string, error)
 	ListPkgs(*CmdToListPkgs) (SoftwarePkgsDTO, error)
 	UpdateApplication(*CmdToUpdateSoftwarePkgApplication) (string, error)
+	ListCheckItems(*CmdToListCheckItems) []CheckItemDTO
+	ListRevisions(string) ([]SoftwarePkgRevisionDTO, string, error)
+	DiffRevisions(*CmdToDiffRevisions) ([]ApplicationFieldDiffDTO, string, error)
+	ListDependencies(string) (SoftwarePkgDependenciesDTO, string, error)
+	ListSimilarPkgs(*CmdToListSimilarPkgs) ([]SimilarSoftwarePkgDTO, error)
+
+	Review(string, *domain.User, []domain.CheckItemReview) (string, error)
+	GetReview(string) ([]CheckItemReviewDTO, string, error)
 
 	Approve(string, *domain.User) (string, error)
 	Reject(string, *domain.User) (string, error)
 	Abandon(string, *domain.User) (string, error)
 	RerunCI(string, *domain.User) (string, error)
+	Retire(string, *domain.User) (string, error)
+	Reopen(string, *domain.User) (string, error)
 	NewReviewComment(string, *CmdToWriteSoftwarePkgReviewComment) (string, error)
 
 	TranslateReviewComment(*CmdToTranslateReviewComment) (
@@ -47,34 +60,54 @@ func NewSoftwarePkgService(
 	sensitive sensitivewords.SensitiveWords,
 	maintainer maintainer.Maintainer,
 	translation translation.Translation,
+	specParser specparser.SpecParser,
+	cla clavalidator.ClaValidator,
+	claCfg *CLAConfig,
 ) *softwarePkgService {
 	robot, _ := dp.NewAccount(softwarePkgRobot)
 
 	return &softwarePkgService{
+		cla:         newCLAChecker(claCfg, cla),
 		repo:        repo,
 		robot:       robot,
 		message:     message,
 		sensitive:   sensitive,
 		maintainer:  maintainer,
 		translation: translation,
+		specParser:  specParser,
 		pkgService:  service.NewPkgService(manager, message),
+
+		dependencyService: service.NewDependencyService(repo, manager),
+		similarityService: service.NewSimilarityService(repo),
 	}
 }
 
 type softwarePkgService struct {
+	cla         *claChecker
 	repo        repository.SoftwarePkg
 	robot       dp.Account
 	message     message.SoftwarePkgMessage
 	sensitive   sensitivewords.SensitiveWords
 	maintainer  maintainer.Maintainer
 	translation translation.Translation
+	specParser  specparser.SpecParser
 	pkgService  service.SoftwarePkgService
+
+	dependencyService service.DependencyService
+	similarityService service.SimilarityService
 }
 
 func (s *softwarePkgService) ApplyNewPkg(cmd *CmdToApplyNewSoftwarePkg) (
 	dto NewSoftwarePkgDTO, code string, err error,
 ) {
-	v := domain.NewSoftwarePkg(&cmd.Importer, cmd.PkgName, &cmd.Application)
+	if code, err = s.cla.check(&cmd.Importer); err != nil {
+		return
+	}
+
+	if code, err = s.checkSensitiveContent(&cmd.Application); err != nil {
+		return
+	}
+
 	if s.pkgService.IsPkgExisted(cmd.PkgName) {
 		err = errors.New("software package already existed")
 		code = errorSoftwarePkgExists
@@ -82,24 +115,33 @@ func (s *softwarePkgService) ApplyNewPkg(cmd *CmdToApplyNewSoftwarePkg) (
 		return
 	}
 
+	spec, err := s.specParser.Parse(cmd.Application.SourceCode.SpecURL)
+	if err != nil {
+		code = errorSoftwarePkgInvalidSpec
+
+		return
+	}
+
+	s.dependencyService.ResolveExistingDeps(&spec)
+
+	v, err := domain.NewSoftwarePkg(&cmd.Importer, cmd.PkgName, &cmd.Application, &spec)
+	if err != nil {
+		code = domain.ParseErrorCode(err)
+
+		return
+	}
+
+	similar, warning := s.similarPkgsWarning(cmd.PkgName, cmd.Application.SourceCode.Upstream)
+
+	// the event will be published by the outbox relay
 	if err = s.repo.AddSoftwarePkg(&v); err != nil {
 		if commonrepo.IsErrorDuplicateCreating(err) {
 			code = errorSoftwarePkgExists
 		}
 	} else {
 		dto.Id = v.Id
-
-		e := domain.NewSoftwarePkgAppliedEvent(&v)
-		if err1 := s.message.NotifyPkgApplied(&e); err1 != nil {
-			logrus.Errorf(
-				"failed to notify a new applying pkg:%s, err:%s",
-				v.Id, err1.Error(),
-			)
-		} else {
-			logrus.Debugf(
-				"successfully to notify a new applying pkg:%s", v.Id,
-			)
-		}
+		dto.Warning = warning
+		dto.SimilarPkgs = similar
 	}
 
 	return
@@ -114,32 +156,100 @@ func (s *softwarePkgService) ListPkgs(cmd *CmdToListPkgs) (SoftwarePkgsDTO, erro
 	return toSoftwarePkgsDTO(v, total), nil
 }
 
+func (s *softwarePkgService) ListCheckItems(cmd *CmdToListCheckItems) []CheckItemDTO {
+	return toCheckItemDTOs(domain.CheckItems(cmd.Sig, cmd.Platform))
+}
+
 func (s *softwarePkgService) UpdateApplication(cmd *CmdToUpdateSoftwarePkgApplication) (string, error) {
+	if code, err := s.cla.check(&cmd.Importer); err != nil {
+		return code, err
+	}
+
+	if code, err := s.checkSensitiveContent(&cmd.Application); err != nil {
+		return code, err
+	}
+
+	// the spec is fetched and resolved only once, rather than each time
+	// the update is retried on conflict.
+	spec, code, err := s.specToUpdate(cmd)
+	if err != nil {
+		return code, err
+	}
+
+	return retryOnConflict(func() (string, error) {
+		return s.updateApplication(cmd, spec)
+	})
+}
+
+// specToUpdate parses the spec if the spec url is changed. It returns nil
+// if the spec of pkg is kept.
+func (s *softwarePkgService) specToUpdate(cmd *CmdToUpdateSoftwarePkgApplication) (
+	*domain.SoftwarePkgSpec, string, error,
+) {
+	pkg, _, err := s.repo.FindSoftwarePkgBasicInfo(cmd.PkgId)
+	if err != nil {
+		return nil, errorCodeForFindingPkg(err), err
+	}
+
+	if !isSpecChanged(&pkg, cmd) {
+		return nil, "", nil
+	}
+
+	spec, err := s.specParser.Parse(cmd.Application.SourceCode.SpecURL)
+	if err != nil {
+		return nil, errorSoftwarePkgInvalidSpec, err
+	}
+
+	s.dependencyService.ResolveExistingDeps(&spec)
+
+	return &spec, "", nil
+}
+
+// isSpecChanged returns true if the spec url is changed. The spec of the pkg
+// applied before the spec was parsed is empty, so it is regarded as changed.
+func isSpecChanged(pkg *domain.SoftwarePkgBasicInfo, cmd *CmdToUpdateSoftwarePkgApplication) bool {
+	return pkg.Spec.Name == "" ||
+		cmd.Application.SourceCode.SpecURL.URL() != pkg.Application.SourceCode.SpecURL.URL()
+}
+
+func (s *softwarePkgService) updateApplication(
+	cmd *CmdToUpdateSoftwarePkgApplication, spec *domain.SoftwarePkgSpec,
+) (string, error) {
 	pkg, version, err := s.repo.FindSoftwarePkgBasicInfo(cmd.PkgId)
 	if err != nil {
 		return errorCodeForFindingPkg(err), err
 	}
 
-	if err = pkg.UpdateApplication(&cmd.Application, &cmd.Importer); err != nil {
-		return domain.ParseErrorCode(err), err
+	if spec == nil {
+		// the spec url was changed by others after the spec was checked.
+		if isSpecChanged(&pkg, cmd) {
+			return errorSoftwarePkgConflict, errors.New("the spec url was updated concurrently")
+		}
+
+		spec = &pkg.Spec
 	}
 
-	if err = s.repo.SaveSoftwarePkg(&pkg, version); err == nil {
-		s.addOperationLog(cmd.Importer.Account, dp.PackageOperationLogActionUpdate, cmd.PkgId)
+	if err = pkg.UpdateApplication(&cmd.Application, spec, &cmd.Importer); err != nil {
+		return domain.ParseErrorCode(err), err
 	}
 
-	return "", err
+	// the reviews of the check items affected by the update are stale now,
+	// and they will be saved with the pkg. The event to rerun ci will be
+	// published by the outbox relay.
+	return "", s.repo.SaveSoftwarePkg(&pkg, version)
 }
 
-func (s *softwarePkgService) addOperationLog(
-	user dp.Account, action dp.PackageOperationLogAction, pkgId string,
-) {
-	log := domain.NewSoftwarePkgOperationLog(user, action, pkgId)
-
-	if err := s.repo.AddOperationLog(&log); err != nil {
+// addRobotCommentToPkg adds a robot comment to the pkg, and it will be saved with the pkg.
+func addRobotCommentToPkg(pkg *domain.SoftwarePkgBasicInfo, robot dp.Account, content string) {
+	v, err := dp.NewReviewComment(content)
+	if err != nil {
 		logrus.Errorf(
-			"add operation log failed, log:%s, err:%s",
-			log.String(), err.Error(),
+			"failed to new a robot comment for pkg:%s, err:%s",
+			pkg.Id, err.Error(),
 		)
+
+		return
 	}
+
+	pkg.AddComment(robot, v)
 }
@@ -0,0 +1,15 @@
+package app
+
+func (s *softwarePkgService) ListDependencies(pid string) (SoftwarePkgDependenciesDTO, string, error) {
+	pkg, _, err := s.repo.FindSoftwarePkgBasicInfo(pid)
+	if err != nil {
+		return SoftwarePkgDependenciesDTO{}, errorCodeForFindingPkg(err), err
+	}
+
+	v, err := s.dependencyService.AnalyzeDependencies(&pkg)
+	if err != nil {
+		return SoftwarePkgDependenciesDTO{}, "", err
+	}
+
+	return toSoftwarePkgDependenciesDTO(v), "", nil
+}
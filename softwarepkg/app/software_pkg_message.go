@@ -1,19 +1,33 @@
 package app
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 
 	commonrepo "github.com/opensourceways/software-package-server/common/domain/repository"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
-	"github.com/opensourceways/software-package-server/softwarepkg/domain/message"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/pkgci"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/pkgmanager"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/repository"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/srpmreader"
 )
 
+// maxConcurrentSrcRPMInspections is the max number of src.rpms inspected
+// at the same time.
+const maxConcurrentSrcRPMInspections = 2
+
+// ciTimeoutPageSize is the number of pkgs loaded at a time when handling
+// the ci timeout.
+const ciTimeoutPageSize = 100
+
+// errorNothingChanged stops updating the pkg when there is nothing to save.
+var errorNothingChanged = errors.New("nothing changed")
+
 type SoftwarePkgMessageService interface {
 	HandlePkgCIChecking(CmdToHandlePkgCIChecking) error
 	HandlePkgCIChecked(CmdToHandlePkgCIChecked) error
@@ -21,22 +35,27 @@ type SoftwarePkgMessageService interface {
 	HandlePkgInitialized(CmdToHandlePkgInitialized) error
 	HandlePkgRepoCreated(CmdToHandlePkgRepoCreated) error
 	HandlePkgAlreadyExisted(CmdToHandlePkgAlreadyExisted) error
+	HandleCITimeout() error
+	Exit()
 }
 
 func NewSoftwarePkgMessageService(
 	ci pkgci.PkgCI,
 	repo repository.SoftwarePkg,
 	manager pkgmanager.PkgManager,
-	message message.SoftwarePkgIndirectMessage,
+	srpm srpmreader.SrcRPMReader,
 ) softwarePkgMessageService {
 	robot, _ := dp.NewAccount(softwarePkgRobot)
 
 	return softwarePkgMessageService{
-		ci:      ci,
-		repo:    repo,
-		robot:   robot,
-		manager: manager,
-		message: message,
+		ci:         ci,
+		repo:       repo,
+		robot:      robot,
+		manager:    manager,
+		srpm:       srpm,
+		inspecting: make(chan struct{}, maxConcurrentSrcRPMInspections),
+
+		inspections: &sync.WaitGroup{},
 	}
 }
 
@@ -45,12 +64,18 @@ type softwarePkgMessageService struct {
 	repo    repository.SoftwarePkg
 	robot   dp.Account
 	manager pkgmanager.PkgManager
-	message message.SoftwarePkgIndirectMessage
+	srpm    srpmreader.SrcRPMReader
+
+	// inspecting limits the number of src.rpms inspected at the same time.
+	inspecting chan struct{}
+
+	// inspections are the inspections of src.rpm in flight.
+	inspections *sync.WaitGroup
 }
 
 // HandlePkgCIChecking
 func (s softwarePkgMessageService) HandlePkgCIChecking(cmd CmdToHandlePkgCIChecking) error {
-	pkg, version, err := s.repo.FindSoftwarePkgBasicInfo(cmd.PkgId)
+	pkg, _, err := s.repo.FindSoftwarePkgBasicInfo(cmd.PkgId)
 	if err != nil {
 		return err
 	}
@@ -64,186 +89,283 @@ func (s softwarePkgMessageService) HandlePkgCIChecking(cmd CmdToHandlePkgCICheck
 		return err
 	}
 
-	pkg.CI.PRNum = prNum
+	err = s.updatePkg(cmd.PkgId, func(pkg *domain.SoftwarePkgBasicInfo) error {
+		if err := pkg.HandleCIChecking(); err != nil {
+			return err
+		}
+
+		pkg.CI.PRNum = prNum
 
-	if err = s.repo.SaveSoftwarePkg(&pkg, version); err != nil {
+		return nil
+	})
+	if err != nil {
 		logrus.Errorf(
 			"save pkg failed when %s, err:%s",
 			cmd.logString(), err.Error(),
 		)
+
+		// the result of the pr would be ignored because its number is not
+		// saved, so close it and send the test again by the retry.
+		if err := s.ci.ClosePR(prNum); err != nil {
+			logrus.Errorf(
+				"close pr:%d failed when %s, err:%s",
+				prNum, cmd.logString(), err.Error(),
+			)
+		}
+
+		return err
 	}
 
+	// the src.rpm may be large, so it is inspected after the pr number is
+	// saved, otherwise the result of ci may arrive before it.
+	s.startToInspectSrcRPM(cmd.PkgId, pkg.Application.SourceCode.SrcRPMURL)
+
 	return nil
 }
 
-// HandlePkgCIChecked
-func (s softwarePkgMessageService) HandlePkgCIChecked(cmd CmdToHandlePkgCIChecked) error {
-	pkg, version, err := s.repo.FindSoftwarePkgBasicInfo(cmd.PkgId)
-	if err != nil {
-		return err
-	}
+// startToInspectSrcRPM blocks until there is a free slot to inspect, so the
+// messages will not be consumed faster than the src.rpms are inspected.
+func (s softwarePkgMessageService) startToInspectSrcRPM(pid string, srcRPMURL dp.URL) {
+	s.inspecting <- struct{}{}
+	s.inspections.Add(1)
 
-	if err := s.ci.ClosePR(cmd.PRNumber); err != nil {
-		logrus.Errorf(
-			"close pr failed when %s, err:%s",
-			cmd.logString(), err.Error(),
-		)
-	}
+	go func() {
+		defer func() {
+			<-s.inspecting
+			s.inspections.Done()
+		}()
 
-	if err := pkg.HandleCIChecked(cmd.Success, cmd.PRNumber); err != nil {
-		return err
-	}
+		s.inspectSrcRPM(pid, srcRPMURL)
+	}()
+}
 
-	s.addCIComment(&cmd)
+// Exit waits for the inspections of src.rpm in flight.
+func (s softwarePkgMessageService) Exit() {
+	s.inspections.Wait()
+}
 
-	if err = s.repo.SaveSoftwarePkg(&pkg, version); err != nil {
+// inspectSrcRPM reads the src.rpm which is tested by the ci and saves the
+// findings as the review notes. The notes are commented only if changed.
+func (s softwarePkgMessageService) inspectSrcRPM(pid string, srcRPMURL dp.URL) {
+	v, readErr := s.srpm.Read(srcRPMURL)
+	if readErr != nil {
 		logrus.Errorf(
-			"save pkg failed when %s, err:%s",
-			cmd.logString(), err.Error(),
+			"failed to read the src.rpm of pkg:%s, err:%s", pid, readErr.Error(),
 		)
 	}
 
-	return nil
-}
+	err := s.updatePkg(pid, func(pkg *domain.SoftwarePkgBasicInfo) error {
+		notes := []string{"Failed to inspect the src.rpm automatically. Please check it manually."}
+		if readErr == nil {
+			notes = pkg.SrcRPMReviewNotes(&v)
+		}
 
-func (s softwarePkgMessageService) addCIComment(cmd *CmdToHandlePkgCIChecked) {
-	content, _ := dp.NewReviewComment(cmd.Detail)
-	comment := domain.NewSoftwarePkgReviewComment(s.robot, content)
+		if !pkg.SetSrcRPMNotes(srcRPMURL, notes) {
+			return errorNothingChanged
+		}
 
-	if err := s.repo.AddReviewComment(cmd.PkgId, &comment); err != nil {
+		addRobotCommentToPkg(
+			pkg, s.robot,
+			"The findings of the src.rpm:\n- "+strings.Join(notes, "\n- "),
+		)
+
+		return nil
+	})
+	if err != nil && err != errorNothingChanged {
 		logrus.Errorf(
-			"failed to add a comment when %s, err:%s",
-			cmd.logString(), err.Error(),
+			"failed to save the src.rpm notes of pkg:%s, err:%s", pid, err.Error(),
 		)
 	}
 }
 
-// HandlePkgRepoCreated
-func (s softwarePkgMessageService) HandlePkgRepoCreated(cmd CmdToHandlePkgRepoCreated) error {
-	if !cmd.isSuccess() {
-		logrus.Errorf(
-			"failed to create repo on platform:%s for pkg:%s, err:%s",
-			cmd.Platform.PackagePlatform(), cmd.PkgId, cmd.FiledReason,
-		)
+// updatePkg reloads the pkg, applies f to it and saves it. It will retry
+// if the pkg was updated concurrently.
+func (s softwarePkgMessageService) updatePkg(
+	pid string, f func(*domain.SoftwarePkgBasicInfo) error,
+) error {
+	_, err := retryOnConflict(func() (string, error) {
+		pkg, version, err := s.repo.FindSoftwarePkgBasicInfo(pid)
+		if err != nil {
+			return "", err
+		}
 
-		return nil
-	}
+		if err := f(&pkg); err != nil {
+			return "", err
+		}
 
-	pkg, version, err := s.repo.FindSoftwarePkgBasicInfo(cmd.PkgId)
-	if err != nil {
-		return err
-	}
+		return "", s.repo.SaveSoftwarePkg(&pkg, version)
+	})
 
-	if err := pkg.HandleRepoCreated(cmd.RepoCreatedInfo); err != nil {
-		return err
-	}
+	return err
+}
 
-	if err := s.repo.SaveSoftwarePkg(&pkg, version); err != nil {
+// HandlePkgCIChecked
+func (s softwarePkgMessageService) HandlePkgCIChecked(cmd CmdToHandlePkgCIChecked) error {
+	if err := s.ci.ClosePR(cmd.PRNumber); err != nil {
 		logrus.Errorf(
-			"save pkg failed when %s, err:%s",
+			"close pr failed when %s, err:%s",
 			cmd.logString(), err.Error(),
 		)
 	}
 
-	return nil
-}
+	err := s.updatePkg(cmd.PkgId, func(pkg *domain.SoftwarePkgBasicInfo) error {
+		if err := pkg.HandleCIChecked(cmd.Success, cmd.PRNumber); err != nil {
+			return err
+		}
 
-// HandlePkgCodeSaved
-func (s softwarePkgMessageService) HandlePkgCodeSaved(cmd CmdToHandlePkgCodeSaved) error {
-	if !cmd.isSuccess() {
-		logrus.Errorf(
-			"failed to create repo on platform:%s for pkg:%s, err:%s",
-			cmd.Platform.PackagePlatform(), cmd.PkgId, cmd.FiledReason,
-		)
+		addRobotCommentToPkg(pkg, s.robot, cmd.Detail)
+
+		return nil
+	})
+
+	// the result arrives late, such as after the ci is timeout or rerun.
+	if err != nil && domain.IsErrorSkipped(err) {
+		logrus.Infof("ignore the late result of ci when %s, err:%s", cmd.logString(), err.Error())
 
 		return nil
 	}
 
-	pkg, version, err := s.repo.FindSoftwarePkgBasicInfo(cmd.PkgId)
-	if err != nil {
-		return err
+	return err
+}
+
+// HandleCITimeout
+func (s softwarePkgMessageService) HandleCITimeout() error {
+	// the ci may run in more than one phase, so only filter by the ci status.
+	opt := repository.OptToFindSoftwarePkgs{
+		CIStatus:     dp.PackageCIStatusRunning,
+		PageNum:      1,
+		CountPerPage: ciTimeoutPageSize,
 	}
 
-	if err := pkg.HandleCodeSaved(cmd.RepoCreatedInfo); err != nil {
+	_, total, err := s.repo.FindSoftwarePkgs(opt)
+	if err != nil || total == 0 {
 		return err
 	}
 
-	if err := s.repo.SaveSoftwarePkg(&pkg, version); err != nil {
-		logrus.Errorf(
-			"save pkg failed when %s, err:%s",
-			cmd.logString(), err.Error(),
-		)
+	// the pkgs which are timeout will not be found again, so the pages are
+	// handled from the last one, which keeps the former pages unchanged.
+	pages := (total + ciTimeoutPageSize - 1) / ciTimeoutPageSize
+	for opt.PageNum = pages; opt.PageNum > 0; opt.PageNum-- {
+		pkgs, _, err := s.repo.FindSoftwarePkgs(opt)
+		if err != nil {
+			return err
+		}
+
+		for i := range pkgs {
+			s.handleCITimeout(pkgs[i].Id)
+		}
 	}
 
 	return nil
 }
 
-// HandlePkgInitialized
-func (s softwarePkgMessageService) HandlePkgInitialized(cmd CmdToHandlePkgInitialized) error {
-	pkg, version, err := s.repo.FindSoftwarePkgBasicInfo(cmd.PkgId)
-	if err != nil {
-		return err
-	}
+func (s softwarePkgMessageService) handleCITimeout(pid string) {
+	prNum := 0
 
-	if cmd.isSuccess() {
-		if err := pkg.HandlePkgInitialized(cmd.RelevantPR); err != nil {
-			return err
-		}
+	err := s.updatePkg(pid, func(pkg *domain.SoftwarePkgBasicInfo) error {
+		prNum = 0
 
-		if !pkg.Application.PackagePlatform.IsLocalPlatform() {
-			s.notifyPkgInitialized(&pkg, &cmd)
+		timeout, changed := pkg.HandleCITimeout()
+		if !changed {
+			return errorNothingChanged
 		}
-	} else {
-		if !cmd.isPkgAreadyExisted() {
-			logrus.Errorf("pkg init failed, pkgid:%s, err:%s", cmd.PkgId, cmd.FiledReason)
 
-			return nil
+		if timeout {
+			addRobotCommentToPkg(
+				pkg, s.robot,
+				"The CI has not finished for a long time and is considered as timeout. Please rerun it.",
+			)
+
+			prNum = pkg.CI.PRNum
 		}
 
-		if err := pkg.HandlePkgAlreadyExisted(); err != nil {
-			return err
+		return nil
+	})
+	if err != nil {
+		if err != errorNothingChanged {
+			logrus.Errorf(
+				"save pkg:%s failed when handling ci timeout, err:%s",
+				pid, err.Error(),
+			)
 		}
 
-		s.addCommentForExistedPkg(&cmd)
+		return
 	}
 
-	if err := s.repo.SaveSoftwarePkg(&pkg, version); err != nil {
+	// close the pr only after the timeout is saved.
+	if prNum > 0 {
+		if err := s.ci.ClosePR(prNum); err != nil {
+			logrus.Errorf(
+				"close pr:%d of pkg:%s failed when handling ci timeout, err:%s",
+				prNum, pid, err.Error(),
+			)
+		}
+	}
+}
+
+// HandlePkgRepoCreated
+func (s softwarePkgMessageService) HandlePkgRepoCreated(cmd CmdToHandlePkgRepoCreated) error {
+	if !cmd.isSuccess() {
 		logrus.Errorf(
-			"save pkg failed when %s, err:%s",
-			cmd.logString(), err.Error(),
+			"failed to create repo on platform:%s for pkg:%s, err:%s",
+			cmd.Platform.PackagePlatform(), cmd.PkgId, cmd.FiledReason,
 		)
+
+		return nil
 	}
 
-	return nil
+	return s.updatePkg(cmd.PkgId, func(pkg *domain.SoftwarePkgBasicInfo) error {
+		return pkg.HandleRepoCreated(cmd.RepoCreatedInfo)
+	})
 }
 
-func (s softwarePkgMessageService) notifyPkgInitialized(
-	pkg *domain.SoftwarePkgBasicInfo, cmd *CmdToHandlePkgInitialized,
-) {
-	e := domain.NewSoftwarePkgInitializedEvent(pkg)
-
-	if err := s.message.NotifyPkgIndirectlyApproved(&e); err != nil {
+// HandlePkgCodeSaved
+func (s softwarePkgMessageService) HandlePkgCodeSaved(cmd CmdToHandlePkgCodeSaved) error {
+	if !cmd.isSuccess() {
 		logrus.Errorf(
-			"failed to notify the pkg was approved indirectly when %s, err:%s",
-			cmd.logString(), err.Error(),
+			"failed to create repo on platform:%s for pkg:%s, err:%s",
+			cmd.Platform.PackagePlatform(), cmd.PkgId, cmd.FiledReason,
 		)
+
+		return nil
 	}
+
+	return s.updatePkg(cmd.PkgId, func(pkg *domain.SoftwarePkgBasicInfo) error {
+		return pkg.HandleCodeSaved(cmd.RepoCreatedInfo)
+	})
 }
 
-func (s softwarePkgMessageService) addCommentForExistedPkg(cmd *CmdToHandlePkgInitialized) {
-	str := fmt.Sprintf(
-		"I'am sorry to close this application. Because the pkg was imported sometimes ago. The repo address is %s. You can work on that repo.",
-		cmd.RepoLink,
-	)
-	content, _ := dp.NewReviewComment(str)
-	comment := domain.NewSoftwarePkgReviewComment(s.robot, content)
+// HandlePkgInitialized
+func (s softwarePkgMessageService) HandlePkgInitialized(cmd CmdToHandlePkgInitialized) error {
+	if cmd.isSuccess() {
+		// the pkg will be notified to be approved indirectly by the
+		// outbox relay if it is not on the local platform.
+		return s.updatePkg(cmd.PkgId, func(pkg *domain.SoftwarePkgBasicInfo) error {
+			return pkg.HandlePkgInitialized(cmd.RelevantPR)
+		})
+	}
 
-	if err := s.repo.AddReviewComment(cmd.PkgId, &comment); err != nil {
-		logrus.Errorf(
-			"failed to add a comment when %s, err:%s",
-			cmd.logString(), err.Error(),
-		)
+	if !cmd.isPkgAreadyExisted() {
+		logrus.Errorf("pkg init failed, pkgid:%s, err:%s", cmd.PkgId, cmd.FiledReason)
+
+		return nil
 	}
+
+	return s.updatePkg(cmd.PkgId, func(pkg *domain.SoftwarePkgBasicInfo) error {
+		if err := pkg.HandlePkgAlreadyExisted(); err != nil {
+			return err
+		}
+
+		addRobotCommentToPkg(
+			pkg, s.robot,
+			fmt.Sprintf(
+				"I'am sorry to close this application. Because the pkg was imported sometimes ago. The repo address is %s. You can work on that repo.",
+				cmd.RepoLink,
+			),
+		)
+
+		return nil
+	})
 }
 
 // HandlePkgAlreadyExisted
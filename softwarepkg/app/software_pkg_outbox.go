@@ -0,0 +1,96 @@
+package app
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/message"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/repository"
+)
+
+const numOfEventsToPublishOnce = 100
+
+type SoftwarePkgOutboxService interface {
+	// PublishPendingEvents publishes the pending events in order.
+	// It stops at the first event which can't be published.
+	PublishPendingEvents() error
+}
+
+func NewSoftwarePkgOutboxService(
+	repo repository.SoftwarePkgOutbox,
+	message message.SoftwarePkgMessage,
+) *softwarePkgOutboxService {
+	return &softwarePkgOutboxService{
+		repo: repo,
+		notifiers: map[string]notifier{
+			domain.SoftwarePkgEventApplied:   message.NotifyPkgApplied,
+			domain.SoftwarePkgEventApproved:  message.NotifyPkgApproved,
+			domain.SoftwarePkgEventToRerunCI: message.NotifyPkgToRerunCI,
+		},
+	}
+}
+
+func NewSoftwarePkgIndirectOutboxService(
+	repo repository.SoftwarePkgOutbox,
+	message message.SoftwarePkgIndirectMessage,
+) *softwarePkgOutboxService {
+	return &softwarePkgOutboxService{
+		repo: repo,
+		notifiers: map[string]notifier{
+			domain.SoftwarePkgEventIndirectlyApproved: message.NotifyPkgIndirectlyApproved,
+		},
+	}
+}
+
+type notifier func(message.EventMessage) error
+
+type softwarePkgOutboxService struct {
+	repo      repository.SoftwarePkgOutbox
+	notifiers map[string]notifier
+}
+
+func (s *softwarePkgOutboxService) PublishPendingEvents() error {
+	events := make([]string, 0, len(s.notifiers))
+	for k := range s.notifiers {
+		events = append(events, k)
+	}
+
+	for {
+		v, err := s.repo.FindPendingEvents(events, numOfEventsToPublishOnce)
+		if err != nil {
+			return err
+		}
+
+		for i := range v {
+			if err := s.publish(&v[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(v) < numOfEventsToPublishOnce {
+			return nil
+		}
+	}
+}
+
+func (s *softwarePkgOutboxService) publish(e *repository.OutboxEvent) error {
+	if err := s.notifiers[e.Event](outboxEventMessage(e.Body)); err != nil {
+		logrus.Errorf(
+			"failed to publish event:%s/%s of pkg:%s, err:%s",
+			e.Event, e.Id, e.PkgId, err.Error(),
+		)
+
+		return err
+	}
+
+	// the event may be published again if it fails to mark,
+	// so the consumers should be idempotent.
+	return s.repo.MarkEventPublished(e.Id)
+}
+
+// outboxEventMessage
+type outboxEventMessage []byte
+
+func (m outboxEventMessage) Message() ([]byte, error) {
+	return m, nil
+}
@@ -2,9 +2,6 @@ package app
 
 import (
 	"errors"
-	"fmt"
-
-	"github.com/sirupsen/logrus"
 
 	commonrepo "github.com/opensourceways/software-package-server/common/domain/repository"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain"
@@ -24,6 +21,10 @@ func (s *softwarePkgService) GetPkgReviewDetail(pid string) (SoftwarePkgReviewDT
 func (s *softwarePkgService) NewReviewComment(
 	pid string, cmd *CmdToWriteSoftwarePkgReviewComment,
 ) (code string, err error) {
+	if code, err = s.cla.check(&cmd.Author); err != nil {
+		return
+	}
+
 	if err = s.sensitive.CheckSensitiveWords(cmd.Content.ReviewComment()); err != nil {
 		if sensitivewords.IsErrorSensitiveInfo(err) {
 			code = errorSoftwarePkgCommentIllegal
@@ -32,25 +33,63 @@ func (s *softwarePkgService) NewReviewComment(
 		return
 	}
 
+	cmds := cmd.Content.ParseReviewCommands()
+	if len(cmds) == 0 {
+		return s.addReviewComment(pid, cmd)
+	}
+
+	// the effects of the commands, the comment and the reply of the
+	// commands are saved together with the pkg.
+	action := func(pkg *domain.SoftwarePkgBasicInfo, author *domain.User) (string, error) {
+		if !pkg.CanAddReviewComment() {
+			return errorSoftwarePkgCannotComment, errors.New("can't comment now")
+		}
+
+		pkg.AddComment(author.Account, cmd.Content)
+
+		return "", s.handleReviewCommands(pkg, author, cmds)
+	}
+
+	return s.doPkgAction(pid, &cmd.Author, action)
+}
+
+func (s *softwarePkgService) addReviewComment(
+	pid string, cmd *CmdToWriteSoftwarePkgReviewComment,
+) (string, error) {
 	pkg, _, err := s.repo.FindSoftwarePkgBasicInfo(pid)
 	if err != nil {
-		code = errorCodeForFindingPkg(err)
-
-		return
+		return errorCodeForFindingPkg(err), err
 	}
 
 	if !pkg.CanAddReviewComment() {
-		code = errorSoftwarePkgCannotComment
-		err = errors.New("can't comment now")
-
-		return
+		return errorSoftwarePkgCannotComment, errors.New("can't comment now")
 	}
 
 	// TODO: there is a critical case that the comment can't be added now
-	comment := domain.NewSoftwarePkgReviewComment(cmd.Author, cmd.Content)
-	err = s.repo.AddReviewComment(pid, &comment)
+	comment := domain.NewSoftwarePkgReviewComment(cmd.Author.Account, cmd.Content)
 
-	return
+	return "", s.repo.AddReviewComments(pid, []domain.SoftwarePkgReviewComment{comment})
+}
+
+// pkgAction changes the pkg on behalf of the user.
+type pkgAction func(pkg *domain.SoftwarePkgBasicInfo, user *domain.User) (string, error)
+
+// doPkgAction loads the pkg, applies the action to it and saves it, so that
+// all the changes made by the action are saved in one transaction. It will
+// retry if the pkg was updated concurrently.
+func (s *softwarePkgService) doPkgAction(pid string, user *domain.User, action pkgAction) (string, error) {
+	return retryOnConflict(func() (string, error) {
+		pkg, version, err := s.repo.FindSoftwarePkgBasicInfo(pid)
+		if err != nil {
+			return errorCodeForFindingPkg(err), err
+		}
+
+		if code, err := action(&pkg, user); err != nil {
+			return code, err
+		}
+
+		return "", s.repo.SaveSoftwarePkg(&pkg, version)
+	})
 }
 
 func (s *softwarePkgService) TranslateReviewComment(
@@ -95,145 +134,178 @@ func (s *softwarePkgService) TranslateReviewComment(
 	return
 }
 
-func (s *softwarePkgService) Approve(pid string, user *domain.User) (code string, err error) {
-	pkg, version, err := s.repo.FindSoftwarePkgBasicInfo(pid)
+func (s *softwarePkgService) GetReview(pid string) ([]CheckItemReviewDTO, string, error) {
+	pkg, _, err := s.repo.FindSoftwarePkgBasicInfo(pid)
 	if err != nil {
-		code = errorCodeForFindingPkg(err)
-
-		return
+		return nil, errorCodeForFindingPkg(err), err
 	}
 
-	isTC, code, err := s.checkPermission(&pkg, user)
-	if err != nil {
-		return
-	}
+	return toCheckItemReviewDTOs(&pkg.Review, pkg.Revision), "", nil
+}
 
-	approved, err := pkg.ApproveBy(&domain.SoftwarePkgApprover{
-		Account: user.Account,
-		IsTC:    isTC,
-	})
-	if err != nil {
-		return
+func (s *softwarePkgService) Review(
+	pid string, user *domain.User, items []domain.CheckItemReview,
+) (string, error) {
+	if code, err := s.cla.check(user); err != nil {
+		return code, err
 	}
 
-	if err = s.repo.SaveSoftwarePkg(&pkg, version); err != nil {
-		return
+	action := func(pkg *domain.SoftwarePkgBasicInfo, user *domain.User) (string, error) {
+		return s.review(pkg, user, items)
 	}
 
-	if approved {
-		s.notifyPkgApproved(&pkg)
+	return s.doPkgAction(pid, user, action)
+}
+
+// review adds the review of user. The pkg is saved even if it is not
+// approved, so that the concurrent reviews can be detected by its version.
+func (s *softwarePkgService) review(
+	pkg *domain.SoftwarePkgBasicInfo, user *domain.User, items []domain.CheckItemReview,
+) (string, error) {
+	review := domain.UserReview{
+		Reviewer: s.maintainer.Reviewer(pkg, user),
+		Items:    items,
 	}
 
-	s.addOperationLog(user.Account, dp.PackageOperationLogActionApprove, pid)
+	if _, err := pkg.AddReview(&review); err != nil {
+		return domain.ParseErrorCode(err), err
+	}
 
-	return
+	return "", nil
 }
 
-func (s *softwarePkgService) notifyPkgApproved(pkg *domain.SoftwarePkgBasicInfo) {
-	e := domain.NewSoftwarePkgApprovedEvent(pkg)
-	err := s.message.NotifyPkgApproved(&e)
-
-	msg := fmt.Sprintf(
-		"notify that a pkg:%s/%s was approved", pkg.Id, pkg.PkgName.PackageName(),
-	)
-	if err == nil {
-		logrus.Debugf("successfully to %s", msg)
-	} else {
-		logrus.Errorf("failed to %s, err:%s", msg, err.Error())
+func (s *softwarePkgService) Approve(pid string, user *domain.User) (string, error) {
+	if code, err := s.cla.check(user); err != nil {
+		return code, err
 	}
+
+	return s.doPkgAction(pid, user, s.approve)
 }
 
-func (s *softwarePkgService) Reject(pid string, user *domain.User) (code string, err error) {
-	pkg, version, err := s.repo.FindSoftwarePkgBasicInfo(pid)
-	if err != nil {
-		code = errorCodeForFindingPkg(err)
+// approve adds the review which will be saved with the pkg.
+func (s *softwarePkgService) approve(pkg *domain.SoftwarePkgBasicInfo, user *domain.User) (string, error) {
+	reviewer := s.maintainer.Reviewer(pkg, user)
 
-		return
+	if _, _, err := pkg.ApproveBy(&reviewer); err != nil {
+		return domain.ParseErrorCode(err), err
 	}
 
-	reviewer := s.maintainer.Reviewer(&pkg, user)
-	if err = pkg.RejectBy(&reviewer); err != nil {
-		return
+	return "", nil
+}
+
+func (s *softwarePkgService) Reject(pid string, user *domain.User) (string, error) {
+	if code, err := s.cla.check(user); err != nil {
+		return code, err
 	}
 
-	err = s.repo.SaveSoftwarePkg(&pkg, version)
+	return s.doPkgAction(pid, user, s.reject)
+}
 
-	return
+func (s *softwarePkgService) reject(pkg *domain.SoftwarePkgBasicInfo, user *domain.User) (string, error) {
+	reviewer := s.maintainer.Reviewer(pkg, user)
+	if err := pkg.RejectBy(&reviewer); err != nil {
+		return domain.ParseErrorCode(err), err
+	}
+
+	return "", nil
 }
 
-func (s *softwarePkgService) Abandon(pid string, user *domain.User) (code string, err error) {
-	pkg, version, err := s.repo.FindSoftwarePkgBasicInfo(pid)
-	if err != nil {
-		code = errorCodeForFindingPkg(err)
+func (s *softwarePkgService) Retire(pid string, user *domain.User) (string, error) {
+	if code, err := s.cla.check(user); err != nil {
+		return code, err
+	}
 
-		return
+	return s.doPkgAction(pid, user, s.retire)
+}
+
+func (s *softwarePkgService) retire(pkg *domain.SoftwarePkgBasicInfo, user *domain.User) (string, error) {
+	reviewer := s.maintainer.Reviewer(pkg, user)
+	if err := pkg.RetireBy(&reviewer); err != nil {
+		return domain.ParseErrorCode(err), err
 	}
 
-	if err = pkg.Abandon(user); err != nil {
-		code = domain.ParseErrorCode(err)
-	} else {
-		err = s.repo.SaveSoftwarePkg(&pkg, version)
+	return "", nil
+}
+
+func (s *softwarePkgService) Reopen(pid string, user *domain.User) (string, error) {
+	if code, err := s.cla.check(user); err != nil {
+		return code, err
 	}
 
-	return
+	return s.doPkgAction(pid, user, s.reopen)
 }
 
-func (s *softwarePkgService) RerunCI(pid string, user *domain.User) (code string, err error) {
-	pkg, version, err := s.repo.FindSoftwarePkgBasicInfo(pid)
-	if err != nil {
-		code = errorCodeForFindingPkg(err)
+// reopen keeps the previous reviews but they are stale now, and they will
+// be saved with the pkg. The event will be published by the outbox relay.
+func (s *softwarePkgService) reopen(pkg *domain.SoftwarePkgBasicInfo, user *domain.User) (string, error) {
+	reviewer := s.maintainer.Reviewer(pkg, user)
+	if err := pkg.ReopenBy(&reviewer); err != nil {
+		return domain.ParseErrorCode(err), err
+	}
 
-		return
+	return s.checkPkgToReopen(pkg)
+}
+
+// checkPkgToReopen checks whether the pkg has been imported or is applied by others.
+func (s *softwarePkgService) checkPkgToReopen(pkg *domain.SoftwarePkgBasicInfo) (string, error) {
+	if s.pkgService.IsPkgExisted(pkg.PkgName) {
+		return errorSoftwarePkgExists, errors.New("software package already existed")
 	}
 
-	changed, err := pkg.RerunCI(user)
+	v, err := s.repo.FindSoftwarePkgsByNames([]dp.PackageName{pkg.PkgName})
 	if err != nil {
-		code = domain.ParseErrorCode(err)
-
-		return
+		return "", err
 	}
 
-	if changed {
-		if err = s.repo.SaveSoftwarePkg(&pkg, version); err != nil {
-			return
+	for i := range v {
+		item := &v[i]
+
+		if item.Id == pkg.Id {
+			continue
+		}
+
+		if !item.Phase.IsClosed() && !item.Phase.IsRetired() {
+			return errorSoftwarePkgExists, errors.New("software package is applied by others")
 		}
 	}
 
-	e := domain.NewSoftwarePkgAppUpdatedEvent(&pkg)
-	if err = s.message.NotifyPkgToRerunCI(&e); err != nil {
-		logrus.Errorf(
-			"failed to notify re-running ci for pkg:%s, err:%s",
-			pkg.Id, err.Error(),
-		)
-	} else {
-		logrus.Debugf(
-			"successfully to notify re-running ci for pkg:%s", pkg.Id,
-		)
+	return "", nil
+}
 
-		s.addCommentToRerunCI(pid)
+func (s *softwarePkgService) Abandon(pid string, user *domain.User) (string, error) {
+	if code, err := s.cla.check(user); err != nil {
+		return code, err
 	}
 
-	return
+	return s.doPkgAction(pid, user, s.abandon)
 }
 
-func (s *softwarePkgService) addCommentToRerunCI(pkgId string) {
-	content, _ := dp.NewReviewComment("The CI will rerun now.")
-	comment := domain.NewSoftwarePkgReviewComment(s.robot, content)
+func (s *softwarePkgService) abandon(pkg *domain.SoftwarePkgBasicInfo, user *domain.User) (string, error) {
+	if err := pkg.Abandon(user); err != nil {
+		return domain.ParseErrorCode(err), err
+	}
 
-	if err := s.repo.AddReviewComment(pkgId, &comment); err != nil {
-		logrus.Errorf(
-			"failed to add a comment when reruns ci for pkg:%s, err:%s",
-			pkgId, err.Error(),
-		)
+	return "", nil
+}
+
+func (s *softwarePkgService) RerunCI(pid string, user *domain.User) (string, error) {
+	if code, err := s.cla.check(user); err != nil {
+		return code, err
 	}
+
+	return s.doPkgAction(pid, user, s.rerunCI)
 }
 
-func (s *softwarePkgService) checkPermission(pkg *domain.SoftwarePkgBasicInfo, user *domain.User) (
-	bool, string, error,
-) {
-	if has, isTC := s.maintainer.HasPermission(pkg, user); has {
-		return isTC, "", nil
+// rerunCI publishes the event by the outbox relay if the ci will rerun.
+func (s *softwarePkgService) rerunCI(pkg *domain.SoftwarePkgBasicInfo, user *domain.User) (string, error) {
+	changed, err := pkg.RerunCI(user)
+	if err != nil {
+		return domain.ParseErrorCode(err), err
+	}
+
+	if changed {
+		addRobotCommentToPkg(pkg, s.robot, "The CI will rerun now.")
 	}
 
-	return false, errorSoftwarePkgNoPermission, errors.New("no permission")
+	return "", nil
 }
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+// handleReviewCommands executes the commands in the comment on behalf of
+// the author, and adds the robot comment which replies the results. All
+// of them will be saved with the pkg.
+func (s *softwarePkgService) handleReviewCommands(
+	pkg *domain.SoftwarePkgBasicInfo, author *domain.User, cmds []dp.ReviewCommand,
+) error {
+	results := make([]string, 0, len(cmds))
+
+	for i := range cmds {
+		cmd := &cmds[i]
+
+		code, err := s.handleReviewCommand(pkg, author, cmd)
+		results = append(results, reviewCommandResult(cmd, code, err))
+	}
+
+	reply, err := dp.NewReviewComment(
+		fmt.Sprintf(
+			"@%s, the results of your commands:\n%s",
+			author.Account.Account(), strings.Join(results, "\n"),
+		),
+	)
+	if err != nil {
+		logrus.Errorf(
+			"failed to reply the commands of pkg:%s, err:%s",
+			pkg.Id, err.Error(),
+		)
+
+		// nothing will be saved, otherwise the effects of the
+		// commands would not be recorded.
+		return err
+	}
+
+	pkg.AddComment(s.robot, reply)
+
+	return nil
+}
+
+func (s *softwarePkgService) handleReviewCommand(
+	pkg *domain.SoftwarePkgBasicInfo, author *domain.User, cmd *dp.ReviewCommand,
+) (string, error) {
+	switch {
+	case cmd.IsApprove():
+		return s.approve(pkg, author)
+
+	case cmd.IsReject():
+		return s.reject(pkg, author)
+
+	case cmd.IsAbandon():
+		return s.abandon(pkg, author)
+
+	case cmd.IsRerunCI():
+		return s.rerunCI(pkg, author)
+
+	case cmd.IsCheckItemReview():
+		index, pass, reason, err := cmd.ParseCheckItemReview()
+		if err != nil {
+			return errorSoftwarePkgInvalidCommand, err
+		}
+
+		return s.review(pkg, author, []domain.CheckItemReview{
+			{Index: index, Pass: pass, Desc: reason},
+		})
+	}
+
+	return errorSoftwarePkgInvalidCommand, errors.New("unsupported command")
+}
+
+func reviewCommandResult(cmd *dp.ReviewCommand, code string, err error) string {
+	if err == nil {
+		return fmt.Sprintf("- %s: done", cmd.String())
+	}
+
+	reason := err.Error()
+	if code == "" {
+		reason = "internal error, please try again later"
+	}
+
+	return fmt.Sprintf("- %s: refused, %s", cmd.String(), reason)
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+)
+
+func (s *softwarePkgService) ListRevisions(pid string) ([]SoftwarePkgRevisionDTO, string, error) {
+	v, err := s.findRevisions(pid)
+	if err != nil {
+		return nil, errorCodeForFindingPkg(err), err
+	}
+
+	return toSoftwarePkgRevisionDTOs(v), "", nil
+}
+
+func (s *softwarePkgService) DiffRevisions(cmd *CmdToDiffRevisions) (
+	[]ApplicationFieldDiffDTO, string, error,
+) {
+	v, err := s.findRevisions(cmd.PkgId)
+	if err != nil {
+		return nil, errorCodeForFindingPkg(err), err
+	}
+
+	from := findRevision(v, cmd.From)
+	if from == nil {
+		return nil, errorSoftwarePkgRevisionNotFound, fmt.Errorf("no revision: %d", cmd.From)
+	}
+
+	to := findRevision(v, cmd.To)
+	if to == nil {
+		return nil, errorSoftwarePkgRevisionNotFound, fmt.Errorf("no revision: %d", cmd.To)
+	}
+
+	return toApplicationFieldDiffDTOs(domain.DiffRevisions(from, to)), "", nil
+}
+
+// findRevisions returns the error of not found if the pkg doesn't exist.
+// The pkg which was applied before the revisions are recorded has none.
+func (s *softwarePkgService) findRevisions(pid string) ([]domain.SoftwarePkgRevision, error) {
+	v, err := s.repo.FindRevisions(pid)
+	if err != nil || len(v) > 0 {
+		return v, err
+	}
+
+	if _, _, err := s.repo.FindSoftwarePkgBasicInfo(pid); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func findRevision(v []domain.SoftwarePkgRevision, number int) *domain.SoftwarePkgRevision {
+	for i := range v {
+		if v[i].Number == number {
+			return &v[i]
+		}
+	}
+
+	return nil
+}
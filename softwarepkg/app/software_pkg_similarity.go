@@ -0,0 +1,49 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+func (s *softwarePkgService) ListSimilarPkgs(cmd *CmdToListSimilarPkgs) ([]SimilarSoftwarePkgDTO, error) {
+	v, err := s.similarityService.FindSimilarPkgs(cmd.PkgName, cmd.Upstream)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSimilarSoftwarePkgDTOs(v), nil
+}
+
+// similarPkgsWarning warns the importer that the pkg may have been applied
+// under another name. It is not fatal to apply if failed to find them.
+func (s *softwarePkgService) similarPkgsWarning(name dp.PackageName, upstream dp.URL) (
+	[]SimilarSoftwarePkgDTO, string,
+) {
+	v, err := s.similarityService.FindSimilarPkgs(name, upstream)
+	if err != nil {
+		logrus.Errorf(
+			"failed to find the pkgs similar to %s, err:%s",
+			name.PackageName(), err.Error(),
+		)
+
+		return nil, ""
+	}
+
+	if len(v) == 0 {
+		return nil, ""
+	}
+
+	names := make([]string, len(v))
+	for i := range v {
+		names[i] = v[i].PkgName.PackageName()
+	}
+
+	return toSimilarSoftwarePkgDTOs(v), fmt.Sprintf(
+		"the pkg may be the same software as: %s, please make sure it is not a duplicate",
+		strings.Join(names, ", "),
+	)
+}
@@ -0,0 +1,184 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/opensourceways/software-package-server/common/allerror"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/sensitivewords"
+)
+
+// ValidateNewPkg checks the application as it would be applied, and returns
+// all the problems at once. Nothing will be saved, but the existed pkg is
+// notified as applying it does.
+func (s *softwarePkgService) ValidateNewPkg(cmd *CmdToValidateNewSoftwarePkg) (
+	ApplicationValidationDTO, error,
+) {
+	if err := s.validateImporter(cmd); err != nil {
+		return ApplicationValidationDTO{}, err
+	}
+
+	if err := s.validatePkgName(cmd); err != nil {
+		return ApplicationValidationDTO{}, err
+	}
+
+	s.validateSpec(cmd)
+
+	if err := s.validateContent(cmd); err != nil {
+		return ApplicationValidationDTO{}, err
+	}
+
+	return ApplicationValidationDTO{
+		Valid:    len(cmd.Problems) == 0,
+		Problems: cmd.Problems,
+	}, nil
+}
+
+func (s *softwarePkgService) validateImporter(cmd *CmdToValidateNewSoftwarePkg) error {
+	code, err := s.cla.check(&cmd.Importer)
+	if err != nil && code != "" {
+		cmd.AddProblem(FieldImporter, code, err.Error())
+
+		return nil
+	}
+
+	return err
+}
+
+func (s *softwarePkgService) validatePkgName(cmd *CmdToValidateNewSoftwarePkg) error {
+	if cmd.PkgName == nil {
+		return nil
+	}
+
+	if s.pkgService.IsPkgExisted(cmd.PkgName) {
+		cmd.AddProblem(FieldPkgName, errorSoftwarePkgExists, "software package already existed")
+	}
+
+	pkgs, err := s.repo.FindSoftwarePkgsByNames([]dp.PackageName{cmd.PkgName})
+	if err != nil {
+		return err
+	}
+
+	for i := range pkgs {
+		if !pkgs[i].Phase.IsClosed() {
+			cmd.AddProblem(FieldPkgName, errorSoftwarePkgExists, "software package has been applied")
+
+			break
+		}
+	}
+
+	for _, err := range dp.CheckPackageNamePolicy(cmd.PkgName, cmd.Application.SourceCode.Upstream) {
+		cmd.AddProblem(FieldPkgName, domain.ParseErrorCode(err), err.Error())
+	}
+
+	return nil
+}
+
+func (s *softwarePkgService) validateSpec(cmd *CmdToValidateNewSoftwarePkg) {
+	if cmd.Application.SourceCode.SpecURL == nil {
+		return
+	}
+
+	spec, err := s.specParser.Parse(cmd.Application.SourceCode.SpecURL)
+	if err != nil {
+		cmd.AddProblem(domain.ApplicationFieldSpecURL, errorSoftwarePkgInvalidSpec, err.Error())
+
+		return
+	}
+
+	if cmd.PkgName == nil {
+		return
+	}
+
+	if err := spec.CheckName(cmd.PkgName); err != nil {
+		cmd.AddProblem(domain.ApplicationFieldSpecURL, domain.ParseErrorCode(err), err.Error())
+	}
+}
+
+func (s *softwarePkgService) validateContent(cmd *CmdToValidateNewSoftwarePkg) error {
+	v, err := s.findSensitiveContent(&cmd.Application)
+	if err != nil {
+		return err
+	}
+
+	cmd.Problems = append(cmd.Problems, v...)
+
+	return nil
+}
+
+// findSensitiveContent checks the desc and the reason of application, and
+// returns the problems of the fields whose content is sensitive. The error
+// of the last field which can't be checked will be returned together.
+func (s *softwarePkgService) findSensitiveContent(app *domain.SoftwarePkgApplication) (
+	r []ApplicationProblemDTO, err error,
+) {
+	check := func(field, content string) {
+		e := s.sensitive.CheckSensitiveWords(content)
+		if e == nil {
+			return
+		}
+
+		if !sensitivewords.IsErrorSensitiveInfo(e) {
+			err = e
+
+			return
+		}
+
+		r = append(r, ApplicationProblemDTO{
+			Field:    field,
+			Code:     allerror.ErrorCodeSensitiveContent,
+			Msg:      e.Error(),
+			Segments: sensitivewords.SensitiveSegments(e),
+		})
+	}
+
+	if app.PackageDesc != nil {
+		check(domain.ApplicationFieldDesc, app.PackageDesc.PackageDesc())
+	}
+
+	if app.ReasonToImportPkg != nil {
+		check(domain.ApplicationFieldReason, app.ReasonToImportPkg.ReasonToImportPkg())
+	}
+
+	return
+}
+
+// checkSensitiveContent returns the error of all the fields of application
+// whose content is sensitive. Whether the content which can't be checked is
+// accepted is decided by the sensitive words service, as the comments are.
+func (s *softwarePkgService) checkSensitiveContent(app *domain.SoftwarePkgApplication) (string, error) {
+	v, err := s.findSensitiveContent(app)
+	if len(v) > 0 {
+		return allerror.ErrorCodeSensitiveContent, errorSensitiveContent{v}
+	}
+
+	return "", err
+}
+
+// errorSensitiveContent carries all the problems of the sensitive content.
+type errorSensitiveContent struct {
+	problems []ApplicationProblemDTO
+}
+
+func (e errorSensitiveContent) Error() string {
+	v := make([]string, len(e.problems))
+	for i := range e.problems {
+		v[i] = fmt.Sprintf("%s: %s", e.problems[i].Field, e.problems[i].Msg)
+	}
+
+	return strings.Join(v, "; ")
+}
+
+// SensitiveProblems returns the problems of the fields whose content is
+// sensitive if err is caused by them.
+func SensitiveProblems(err error) []ApplicationProblemDTO {
+	var e errorSensitiveContent
+	if errors.As(err, &e) {
+		return e.problems
+	}
+
+	return nil
+}
@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"encoding/json"
+
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 
@@ -20,14 +22,24 @@ func AddRouteForSoftwarePkgController(r *gin.RouterGroup, pkgService app.Softwar
 
 	m := middleware.UserChecking().CheckUser
 	r.POST("/v1/softwarepkg", m, ctl.ApplyNewPkg)
+	r.POST("/v1/softwarepkg/validate", m, ctl.ValidateNewPkg)
 	r.GET("/v1/softwarepkg", ctl.ListPkgs)
+	r.GET("/v1/softwarepkg/checkitems", ctl.ListCheckItems)
+	r.GET("/v1/softwarepkg/similar", m, ctl.ListSimilarPkgs)
 	r.GET("/v1/softwarepkg/:id", ctl.Get)
 	r.PUT("/v1/softwarepkg/:id", m, ctl.UpdateApplication)
+	r.GET("/v1/softwarepkg/:id/revisions", ctl.ListRevisions)
+	r.GET("/v1/softwarepkg/:id/revisions/diff", ctl.DiffRevisions)
+	r.GET("/v1/softwarepkg/:id/dependencies", ctl.ListDependencies)
 
+	r.POST("/v1/softwarepkg/:id/review", m, ctl.Review)
+	r.GET("/v1/softwarepkg/:id/review", ctl.GetReview)
 	r.PUT("/v1/softwarepkg/:id/review/approve", m, ctl.Approve)
 	r.PUT("/v1/softwarepkg/:id/review/reject", m, ctl.Reject)
 	r.PUT("/v1/softwarepkg/:id/review/abandon", m, ctl.Abandon)
 	r.PUT("/v1/softwarepkg/:id/review/rerunci", m, ctl.RerunCI)
+	r.PUT("/v1/softwarepkg/:id/retire", m, ctl.Retire)
+	r.PUT("/v1/softwarepkg/:id/reopen", m, ctl.Reopen)
 	r.POST("/v1/softwarepkg/:id/review/comment", m, ctl.NewReviewComment)
 	r.POST("/v1/softwarepkg/:id/review/comment/:cid/translate", m, ctl.TranslateReviewComment)
 }
@@ -64,12 +76,57 @@ func (ctl SoftwarePkgController) ApplyNewPkg(ctx *gin.Context) {
 	}
 
 	if r, code, err := ctl.service.ApplyNewPkg(&cmd); err != nil {
-		commonctl.SendFailedResp(ctx, code, err)
+		sendFailedRespOfApplication(ctx, code, err)
 	} else {
 		commonctl.SendRespOfPost(ctx, r)
 	}
 }
 
+// sendFailedRespOfApplication returns all the sensitive problems of the
+// application in the data of response if there are.
+func sendFailedRespOfApplication(ctx *gin.Context, code string, err error) {
+	if v := app.SensitiveProblems(err); len(v) > 0 {
+		commonctl.SendFailedRespWithData(ctx, code, err, v)
+	} else {
+		commonctl.SendFailedResp(ctx, code, err)
+	}
+}
+
+// ValidateNewPkg
+// @Summary validate the application of a new software package
+// @Description check the application as applying it, and return all the problems at once. Nothing will be saved.
+// @Tags  SoftwarePkg
+// @Accept json
+// @Param	param  body	 softwarePkgRequest	 true	"body of applying a new software package"
+// @Success 200 {object} app.ApplicationValidationDTO
+// @Failure 400 {object} ResponseData
+// @Router /v1/softwarepkg/validate [post]
+func (ctl SoftwarePkgController) ValidateNewPkg(ctx *gin.Context) {
+	// the missing fields are the problems to be returned, so the body
+	// is not validated by the binding.
+	var req softwarePkgRequest
+	if err := json.NewDecoder(ctx.Request.Body).Decode(&req); err != nil {
+		commonctl.SendBadRequestBody(ctx, err)
+
+		return
+	}
+
+	user, err := middleware.UserChecking().FetchUser(ctx)
+	if err != nil {
+		commonctl.SendFailedResp(ctx, "", err)
+
+		return
+	}
+
+	cmd := req.toValidationCmd(&user)
+
+	if v, err := ctl.service.ValidateNewPkg(&cmd); err != nil {
+		commonctl.SendFailedResp(ctx, "", err)
+	} else {
+		commonctl.SendRespOfGet(ctx, v)
+	}
+}
+
 // ListPkgs
 // @Summary list software packages
 // @Description list software packages
@@ -104,6 +161,66 @@ func (ctl SoftwarePkgController) ListPkgs(ctx *gin.Context) {
 	}
 }
 
+// ListCheckItems
+// @Summary list check items
+// @Description list the check items that software package will be reviewed against
+// @Tags  SoftwarePkg
+// @Accept json
+// @Param    sig        query	 string   false    "sig of the softwarePkg"
+// @Param    platform   query	 string   false    "platform of the softwarePkg"
+// @Success 200 {object} app.CheckItemDTO
+// @Failure 400 {object} ResponseData
+// @Router /v1/softwarepkg/checkitems [get]
+func (ctl SoftwarePkgController) ListCheckItems(ctx *gin.Context) {
+	var req checkItemsQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		commonctl.SendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	cmd, err := req.toCmd()
+	if err != nil {
+		commonctl.SendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	commonctl.SendRespOfGet(ctx, ctl.service.ListCheckItems(&cmd))
+}
+
+// ListSimilarPkgs
+// @Summary list similar software packages
+// @Description list the imported and in-flight software packages which may be the same software
+// @Tags  SoftwarePkg
+// @Accept json
+// @Param    name       query	 string   true     "name of the softwarePkg"
+// @Param    upstream   query	 string   false    "upstream of the softwarePkg"
+// @Success 200 {object} app.SimilarSoftwarePkgDTO
+// @Failure 400 {object} ResponseData
+// @Router /v1/softwarepkg/similar [get]
+func (ctl SoftwarePkgController) ListSimilarPkgs(ctx *gin.Context) {
+	var req similarPkgsQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		commonctl.SendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	cmd, err := req.toCmd()
+	if err != nil {
+		commonctl.SendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if v, err := ctl.service.ListSimilarPkgs(&cmd); err != nil {
+		commonctl.SendFailedResp(ctx, "", err)
+	} else {
+		commonctl.SendRespOfGet(ctx, v)
+	}
+}
+
 // Get
 // @Summary get software package
 // @Description get software package
@@ -121,6 +238,124 @@ func (ctl SoftwarePkgController) Get(ctx *gin.Context) {
 	}
 }
 
+// ListRevisions
+// @Summary list revisions of software package
+// @Description list the revisions of the application of software package
+// @Tags  SoftwarePkg
+// @Accept json
+// @Param    id         path	string  true    "id of software package"
+// @Success 200 {object} app.SoftwarePkgRevisionDTO
+// @Failure 400 {object} ResponseData
+// @Router /v1/softwarepkg/{id}/revisions [get]
+func (ctl SoftwarePkgController) ListRevisions(ctx *gin.Context) {
+	if v, code, err := ctl.service.ListRevisions(ctx.Param("id")); err != nil {
+		commonctl.SendFailedResp(ctx, code, err)
+	} else {
+		commonctl.SendRespOfGet(ctx, v)
+	}
+}
+
+// ListDependencies
+// @Summary list dependencies of software package
+// @Description list the BuildRequires and Requires of the spec and whether they are available
+// @Tags  SoftwarePkg
+// @Accept json
+// @Param    id         path	string  true    "id of software package"
+// @Success 200 {object} app.SoftwarePkgDependenciesDTO
+// @Failure 400 {object} ResponseData
+// @Router /v1/softwarepkg/{id}/dependencies [get]
+func (ctl SoftwarePkgController) ListDependencies(ctx *gin.Context) {
+	if v, code, err := ctl.service.ListDependencies(ctx.Param("id")); err != nil {
+		commonctl.SendFailedResp(ctx, code, err)
+	} else {
+		commonctl.SendRespOfGet(ctx, v)
+	}
+}
+
+// DiffRevisions
+// @Summary diff two revisions of software package
+// @Description list the fields of application which are changed between two revisions
+// @Tags  SoftwarePkg
+// @Accept json
+// @Param    id         path	string  true    "id of software package"
+// @Param    from       query	int     true    "number of the old revision"
+// @Param    to         query	int     true    "number of the new revision"
+// @Success 200 {object} app.ApplicationFieldDiffDTO
+// @Failure 400 {object} ResponseData
+// @Router /v1/softwarepkg/{id}/revisions/diff [get]
+func (ctl SoftwarePkgController) DiffRevisions(ctx *gin.Context) {
+	var req revisionDiffQuery
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		commonctl.SendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	cmd := req.toCmd(ctx.Param("id"))
+
+	if v, code, err := ctl.service.DiffRevisions(&cmd); err != nil {
+		commonctl.SendFailedResp(ctx, code, err)
+	} else {
+		commonctl.SendRespOfGet(ctx, v)
+	}
+}
+
+// Review
+// @Summary review software package
+// @Description review the check items of software package
+// @Tags  SoftwarePkg
+// @Accept json
+// @Param	id     path	 string	         true	"id of software package"
+// @Param	param  body	 reviewRequest	 true	"body of reviewing software package"
+// @Success 201 {object} ResponseData
+// @Failure 400 {object} ResponseData
+// @Router /v1/softwarepkg/{id}/review [post]
+func (ctl SoftwarePkgController) Review(ctx *gin.Context) {
+	var req reviewRequest
+	if err := ctx.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+		commonctl.SendBadRequestBody(ctx, err)
+
+		return
+	}
+
+	user, err := middleware.UserChecking().FetchUser(ctx)
+	if err != nil {
+		commonctl.SendFailedResp(ctx, "", err)
+
+		return
+	}
+
+	cmd, err := req.toCmd()
+	if err != nil {
+		commonctl.SendBadRequestParam(ctx, err)
+
+		return
+	}
+
+	if code, err := ctl.service.Review(ctx.Param("id"), &user, cmd); err != nil {
+		commonctl.SendFailedResp(ctx, code, err)
+	} else {
+		commonctl.SendRespOfCreate(ctx)
+	}
+}
+
+// GetReview
+// @Summary get review of software package
+// @Description get the review result of each check item of software package
+// @Tags  SoftwarePkg
+// @Accept json
+// @Param	id  path	 string	 true	"id of software package"
+// @Success 200 {array} app.CheckItemReviewDTO
+// @Failure 400 {object} ResponseData
+// @Router /v1/softwarepkg/{id}/review [get]
+func (ctl SoftwarePkgController) GetReview(ctx *gin.Context) {
+	if v, code, err := ctl.service.GetReview(ctx.Param("id")); err != nil {
+		commonctl.SendFailedResp(ctx, code, err)
+	} else {
+		commonctl.SendRespOfGet(ctx, v)
+	}
+}
+
 // Approve
 // @Summary approve software package
 // @Description approve software package
@@ -169,6 +404,54 @@ func (ctl SoftwarePkgController) Reject(ctx *gin.Context) {
 	}
 }
 
+// Retire
+// @Summary retire software package
+// @Description retire the imported software package, only tc can do it
+// @Tags  SoftwarePkg
+// @Accept json
+// @Param	id  path	 string	 true	"id of software package"
+// @Success 202 {object} ResponseData
+// @Failure 400 {object} ResponseData
+// @Router /v1/softwarepkg/{id}/retire [put]
+func (ctl SoftwarePkgController) Retire(ctx *gin.Context) {
+	user, err := middleware.UserChecking().FetchUser(ctx)
+	if err != nil {
+		commonctl.SendFailedResp(ctx, "", err)
+
+		return
+	}
+
+	if code, err := ctl.service.Retire(ctx.Param("id"), &user); err != nil {
+		commonctl.SendFailedResp(ctx, code, err)
+	} else {
+		commonctl.SendRespOfPut(ctx)
+	}
+}
+
+// Reopen
+// @Summary reopen software package
+// @Description reopen the closed software package. The importer can reopen it within a period after it was closed, and tc can reopen it at any time.
+// @Tags  SoftwarePkg
+// @Accept json
+// @Param	id  path	 string	 true	"id of software package"
+// @Success 202 {object} ResponseData
+// @Failure 400 {object} ResponseData
+// @Router /v1/softwarepkg/{id}/reopen [put]
+func (ctl SoftwarePkgController) Reopen(ctx *gin.Context) {
+	user, err := middleware.UserChecking().FetchUser(ctx)
+	if err != nil {
+		commonctl.SendFailedResp(ctx, "", err)
+
+		return
+	}
+
+	if code, err := ctl.service.Reopen(ctx.Param("id"), &user); err != nil {
+		commonctl.SendFailedResp(ctx, code, err)
+	} else {
+		commonctl.SendRespOfPut(ctx)
+	}
+}
+
 // Abandon
 // @Summary abandon software package
 // @Description abandon software package
@@ -195,7 +478,7 @@ func (ctl SoftwarePkgController) Abandon(ctx *gin.Context) {
 
 // NewReviewComment
 // @Summary create a new software package review comment
-// @Description create a new software package review comment
+// @Description create a new software package review comment, the commands in it such as /approve, /reject, /lgtm, /check, /rerun-ci and /abandon will be executed
 // @Tags  SoftwarePkg
 // @Accept json
 // @Param	param  body	 reviewCommentRequest	 true	"body of creating a new software package review comment"
@@ -304,7 +587,7 @@ func (ctl SoftwarePkgController) UpdateApplication(ctx *gin.Context) {
 		},
 	)
 	if err != nil {
-		commonctl.SendFailedResp(ctx, code, err)
+		sendFailedRespOfApplication(ctx, code, err)
 	} else {
 		commonctl.SendRespOfPut(ctx)
 	}
@@ -1,6 +1,9 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/opensourceways/software-package-server/softwarepkg/app"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
@@ -9,6 +12,9 @@ import (
 const (
 	pageNum      = 1
 	countPerPage = 10
+
+	errorMissingField = "missing_field"
+	errorInvalidField = "invalid_field"
 )
 
 type softwarePkgRequest struct {
@@ -20,6 +26,7 @@ type softwarePkgRequest struct {
 	PackageSig      string `json:"sig"             binding:"required"`
 	PackageReason   string `json:"reason"          binding:"required"`
 	PackagePlatform string `json:"platform"        binding:"required"`
+	License         string `json:"license"`
 }
 
 func (s softwarePkgRequest) toCmd(importer *domain.User) (
@@ -65,10 +72,98 @@ func (s softwarePkgRequest) toCmd(importer *domain.User) (
 	}
 
 	application.PackagePlatform, err = dp.NewPackagePlatform(s.PackagePlatform)
+	if err != nil {
+		return
+	}
+
+	// it will be the license declared in the spec if it is not given.
+	if s.License != "" {
+		application.License, err = dp.NewLicense(s.License)
+	}
 
 	return
 }
 
+// toValidationCmd is like toCmd, but it goes on when a field is missing
+// or invalid, so that all the problems are found at once.
+func (s softwarePkgRequest) toValidationCmd(importer *domain.User) app.CmdToValidateNewSoftwarePkg {
+	cmd := app.CmdToValidateNewSoftwarePkg{}
+	cmd.Importer = *importer
+
+	check := func(field, value string, required bool, f func(string) error) {
+		if value == "" {
+			if required {
+				cmd.AddProblem(field, errorMissingField, "missing "+field)
+			}
+
+			return
+		}
+
+		if err := f(value); err != nil {
+			cmd.AddProblem(field, errorInvalidField, err.Error())
+		}
+	}
+
+	check(app.FieldPkgName, s.PackageName, true, func(v string) (err error) {
+		cmd.PkgName, err = dp.NewPackageName(v)
+
+		return
+	})
+
+	application := &cmd.Application
+
+	check(domain.ApplicationFieldSpecURL, s.SpecUrl, true, func(v string) (err error) {
+		application.SourceCode.SpecURL, err = dp.NewURL(v)
+
+		return
+	})
+
+	check(domain.ApplicationFieldUpstream, s.Upstream, true, func(v string) (err error) {
+		application.SourceCode.Upstream, err = dp.NewURL(v)
+
+		return
+	})
+
+	check(domain.ApplicationFieldSrcRPMURL, s.SrcRPMURL, true, func(v string) (err error) {
+		application.SourceCode.SrcRPMURL, err = dp.NewURL(v)
+
+		return
+	})
+
+	check(domain.ApplicationFieldSig, s.PackageSig, true, func(v string) (err error) {
+		application.ImportingPkgSig, err = dp.NewImportingPkgSig(v)
+
+		return
+	})
+
+	check(domain.ApplicationFieldReason, s.PackageReason, true, func(v string) (err error) {
+		application.ReasonToImportPkg, err = dp.NewReasonToImportPkg(v)
+
+		return
+	})
+
+	check(domain.ApplicationFieldDesc, s.PackageDesc, true, func(v string) (err error) {
+		application.PackageDesc, err = dp.NewPackageDesc(v)
+
+		return
+	})
+
+	check(domain.ApplicationFieldPlatform, s.PackagePlatform, true, func(v string) (err error) {
+		application.PackagePlatform, err = dp.NewPackagePlatform(v)
+
+		return
+	})
+
+	// it will be the license declared in the spec if it is not given.
+	check(domain.ApplicationFieldLicense, s.License, false, func(v string) (err error) {
+		application.License, err = dp.NewLicense(v)
+
+		return
+	})
+
+	return cmd
+}
+
 type softwarePkgListQuery struct {
 	Phase        string `json:"phase"          form:"phase"`
 	PkgName      string `json:"pkg_name"       form:"pkg_name"`
@@ -123,7 +218,7 @@ type reviewCommentRequest struct {
 }
 
 func (r reviewCommentRequest) toCmd(user *domain.User) (rc app.CmdToWriteSoftwarePkgReviewComment, err error) {
-	rc.Author = user.Account
+	rc.Author = *user
 
 	rc.Content, err = dp.NewReviewComment(r.Comment)
 
@@ -141,3 +236,92 @@ func (t translationCommentRequest) toCmd(pkgId, commentId string) (cmd app.CmdTo
 
 	return
 }
+
+type checkItemReviewRequest struct {
+	Index int    `json:"index"`
+	Pass  bool   `json:"pass"`
+	Desc  string `json:"desc"`
+}
+
+type reviewRequest struct {
+	Reviews []checkItemReviewRequest `json:"reviews" binding:"required"`
+}
+
+func (r reviewRequest) toCmd() ([]domain.CheckItemReview, error) {
+	if len(r.Reviews) == 0 {
+		return nil, errors.New("no review")
+	}
+
+	items := make([]domain.CheckItemReview, len(r.Reviews))
+	indexes := make(map[int]bool, len(r.Reviews))
+
+	for i := range r.Reviews {
+		v := &r.Reviews[i]
+
+		if indexes[v.Index] {
+			return nil, fmt.Errorf("duplicate check item: %d", v.Index)
+		}
+		indexes[v.Index] = true
+
+		if !v.Pass && v.Desc == "" {
+			return nil, fmt.Errorf("missing the reason why check item: %d is not passed", v.Index)
+		}
+
+		items[i] = domain.CheckItemReview{
+			Index: v.Index,
+			Pass:  v.Pass,
+			Desc:  v.Desc,
+		}
+	}
+
+	return items, nil
+}
+
+type checkItemsQuery struct {
+	Sig      string `json:"sig"       form:"sig"`
+	Platform string `json:"platform"  form:"platform"`
+}
+
+func (q checkItemsQuery) toCmd() (cmd app.CmdToListCheckItems, err error) {
+	if q.Sig != "" {
+		if cmd.Sig, err = dp.NewImportingPkgSig(q.Sig); err != nil {
+			return
+		}
+	}
+
+	if q.Platform != "" {
+		cmd.Platform, err = dp.NewPackagePlatform(q.Platform)
+	}
+
+	return
+}
+
+type similarPkgsQuery struct {
+	Name     string `json:"name"      form:"name"      binding:"required"`
+	Upstream string `json:"upstream"  form:"upstream"`
+}
+
+func (q similarPkgsQuery) toCmd() (cmd app.CmdToListSimilarPkgs, err error) {
+	if cmd.PkgName, err = dp.NewPackageName(q.Name); err != nil {
+		return
+	}
+
+	if q.Upstream != "" {
+		cmd.Upstream, err = dp.NewURL(q.Upstream)
+	}
+
+	return
+}
+
+type revisionDiffQuery struct {
+	From int `json:"from"  form:"from"  binding:"required"`
+	To   int `json:"to"    form:"to"    binding:"required"`
+}
+
+func (q revisionDiffQuery) toCmd(pid string) app.CmdToDiffRevisions {
+	return app.CmdToDiffRevisions{
+		PkgId: pid,
+		From:  q.From,
+		To:    q.To,
+	}
+}
@@ -1,5 +1,18 @@
 package domain
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+const (
+	defaultMinNumApprovedByTC            = 1
+	defaultMinNumApprovedBySigMaintainer = 2
+)
+
 var config Config
 
 func Init(cfg *Config) {
@@ -7,9 +20,33 @@ func Init(cfg *Config) {
 }
 
 type Config struct {
-	EcopkgSig                     string `json:"ecopkg_sig"`
-	MinNumApprovedByTC            int    `json:"min_num_approved_by_tc"`
-	MinNumApprovedBySigMaintainer int    `json:"min_num_approved_by_sig_maintainer"`
+	EcopkgSig string `json:"ecopkg_sig"`
+
+	// MinNumApprovedByTC and MinNumApprovedBySigMaintainer are the quorum
+	// of the approvals. The quorum is disabled if it is set to 0, and it
+	// is the default one if not set.
+	MinNumApprovedByTC            *int `json:"min_num_approved_by_tc"`
+	MinNumApprovedBySigMaintainer *int `json:"min_num_approved_by_sig_maintainer"`
+
+	// CITimeout is the max duration in seconds that the ci can run.
+	CITimeout int64 `json:"ci_timeout"`
+
+	// ReopenWindow is the duration in seconds after the pkg is closed,
+	// within which the importer can reopen it.
+	ReopenWindow int64 `json:"reopen_window"`
+
+	// CheckItems are the items which every pkg should be reviewed against.
+	// They are the built-in ones if empty.
+	CheckItems []CheckItemConfig `json:"check_items"`
+
+	// SigCheckItems are the extra items for the pkg of the specified sig.
+	SigCheckItems []SigCheckItemsConfig `json:"sig_check_items"`
+
+	// License is the config of checking the license of pkg automatically.
+	License LicenseConfig `json:"license"`
+
+	// Similarity is the config of finding the pkgs similar to the new one.
+	Similarity SimilarityConfig `json:"similarity"`
 }
 
 func (cfg *Config) SetDefault() {
@@ -17,11 +54,239 @@ func (cfg *Config) SetDefault() {
 		cfg.EcopkgSig = "ecopkg"
 	}
 
-	if cfg.MinNumApprovedByTC <= 0 {
-		cfg.MinNumApprovedByTC = 1
+	if cfg.MinNumApprovedByTC == nil {
+		v := defaultMinNumApprovedByTC
+		cfg.MinNumApprovedByTC = &v
+	}
+
+	if cfg.MinNumApprovedBySigMaintainer == nil {
+		v := defaultMinNumApprovedBySigMaintainer
+		cfg.MinNumApprovedBySigMaintainer = &v
+	}
+
+	if cfg.CITimeout <= 0 {
+		cfg.CITimeout = 3 * 3600
+	}
+
+	if cfg.ReopenWindow <= 0 {
+		cfg.ReopenWindow = 7 * 24 * 3600
+	}
+
+	if len(cfg.CheckItems) == 0 {
+		cfg.CheckItems = defaultCheckItems()
+	}
+
+	cfg.Similarity.setDefault()
+}
+
+func (cfg *Config) Validate() error {
+	if cfg.minNumApprovedByTC() < 0 || cfg.minNumApprovedBySigMaintainer() < 0 {
+		return errors.New("the min number of approvals can't be negative")
+	}
+
+	if len(cfg.CheckItems) == 0 {
+		return errors.New("missing check items")
+	}
+
+	indexes := map[int]bool{}
+
+	for i := range cfg.CheckItems {
+		if err := cfg.CheckItems[i].validate(indexes); err != nil {
+			return err
+		}
+	}
+
+	sigs := map[string]bool{}
+
+	for i := range cfg.SigCheckItems {
+		item := &cfg.SigCheckItems[i]
+
+		if item.Sig == "" || sigs[item.Sig] {
+			return fmt.Errorf("invalid or duplicate sig: %s of sig check items", item.Sig)
+		}
+		sigs[item.Sig] = true
+
+		for j := range item.Items {
+			// the index of sig check item can't be same as the common one.
+			if err := item.Items[j].validate(indexes); err != nil {
+				return err
+			}
+		}
 	}
 
-	if cfg.MinNumApprovedBySigMaintainer <= 0 {
-		cfg.MinNumApprovedBySigMaintainer = 2
+	if cfg.License.enabled() && !indexes[cfg.License.CheckItem] {
+		return fmt.Errorf("unknown check item: %d of license", cfg.License.CheckItem)
 	}
+
+	return nil
+}
+
+func (cfg *Config) minNumApprovedByTC() int {
+	if cfg.MinNumApprovedByTC == nil {
+		return defaultMinNumApprovedByTC
+	}
+
+	return *cfg.MinNumApprovedByTC
+}
+
+func (cfg *Config) minNumApprovedBySigMaintainer() int {
+	if cfg.MinNumApprovedBySigMaintainer == nil {
+		return defaultMinNumApprovedBySigMaintainer
+	}
+
+	return *cfg.MinNumApprovedBySigMaintainer
+}
+
+// checkItems returns all the common check items and the ones of the sig.
+// The items which are not for the platform will be filtered out.
+// All the items will be returned if the platform is nil.
+func (cfg *Config) checkItems(sig dp.ImportingPkgSig, platform dp.PackagePlatform) []CheckItem {
+	r := make([]CheckItem, 0, len(cfg.CheckItems))
+
+	add := func(items []CheckItemConfig) {
+		for i := range items {
+			if item := &items[i]; item.isForPlatform(platform) {
+				r = append(r, item.toCheckItem())
+			}
+		}
+	}
+
+	add(cfg.CheckItems)
+
+	if sig != nil {
+		for i := range cfg.SigCheckItems {
+			if item := &cfg.SigCheckItems[i]; item.Sig == sig.ImportingPkgSig() {
+				add(item.Items)
+
+				break
+			}
+		}
+	}
+
+	return r
+}
+
+// defaultCheckItems are the check items used when they are not configured,
+// so that the deployment configured before them keeps working.
+func defaultCheckItems() []CheckItemConfig {
+	return []CheckItemConfig{
+		{
+			Index:  1,
+			Title:  "Necessity",
+			Desc:   "The software is necessary to be imported, and it doesn't duplicate an existing one.",
+			Owners: []string{"tc", "sig_maintainer"},
+			Fields: []string{ApplicationFieldReason, ApplicationFieldDesc},
+		},
+		{
+			Index:  2,
+			Title:  "Sig",
+			Desc:   "The software belongs to the sig which will maintain it.",
+			Owners: []string{"sig_maintainer"},
+			Fields: []string{ApplicationFieldSig},
+		},
+		{
+			Index:  3,
+			Title:  "Upstream",
+			Desc:   "The upstream is active and the source code comes from it.",
+			Owners: []string{"sig_maintainer", "committer"},
+			Fields: []string{ApplicationFieldUpstream, ApplicationFieldSrcRPMURL},
+		},
+		{
+			Index:  4,
+			Title:  "License",
+			Desc:   "The license is compliant with the distribution.",
+			Owners: []string{"tc", "sig_maintainer"},
+			Fields: []string{ApplicationFieldLicense, ApplicationFieldSpecURL},
+		},
+		{
+			Index:  5,
+			Title:  "Spec",
+			Desc:   "The spec follows the packaging guidelines and the package can be built.",
+			Owners: []string{"sig_maintainer", "committer"},
+		},
+	}
+}
+
+// CheckItemConfig
+type CheckItemConfig struct {
+	Index int    `json:"index"`
+	Title string `json:"title"      required:"true"`
+	Desc  string `json:"desc"       required:"true"`
+
+	// Owners are the community roles, such as tc, sig_maintainer and committer.
+	Owners []string `json:"owners"     required:"true"`
+
+	// Platforms limits the item to the specified platforms.
+	// It is for all the platforms if empty.
+	Platforms []string `json:"platforms"`
+
+	// Fields are the fields of application which the item is about, such as
+	// spec_url and desc. The reviews of the item will be stale when any of
+	// them is changed. It is about the source code if empty.
+	Fields []string `json:"fields"`
+}
+
+func (cfg *CheckItemConfig) validate(indexes map[int]bool) error {
+	if indexes[cfg.Index] {
+		return fmt.Errorf("duplicate index: %d of check item", cfg.Index)
+	}
+	indexes[cfg.Index] = true
+
+	if cfg.Title == "" || cfg.Desc == "" {
+		return fmt.Errorf("missing title or desc of check item: %d", cfg.Index)
+	}
+
+	if len(cfg.Owners) == 0 {
+		return fmt.Errorf("missing owners of check item: %d", cfg.Index)
+	}
+
+	for _, v := range cfg.Owners {
+		if _, err := dp.NewCommunityRole(v); err != nil {
+			return fmt.Errorf("invalid owner: %s of check item: %d", v, cfg.Index)
+		}
+	}
+
+	for _, v := range cfg.Fields {
+		if !containsField(applicationFields, v) {
+			return fmt.Errorf("invalid field: %s of check item: %d", v, cfg.Index)
+		}
+	}
+
+	return nil
+}
+
+func (cfg *CheckItemConfig) isForPlatform(platform dp.PackagePlatform) bool {
+	if platform == nil || len(cfg.Platforms) == 0 {
+		return true
+	}
+
+	for _, v := range cfg.Platforms {
+		if strings.EqualFold(v, platform.PackagePlatform()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (cfg *CheckItemConfig) toCheckItem() CheckItem {
+	owners := make([]dp.CommunityRole, len(cfg.Owners))
+	for i, v := range cfg.Owners {
+		// it has been validated
+		owners[i], _ = dp.NewCommunityRole(v)
+	}
+
+	return CheckItem{
+		Index:  cfg.Index,
+		Item:   cfg.Title,
+		Desc:   cfg.Desc,
+		Owners: owners,
+		Fields: cfg.Fields,
+	}
+}
+
+// SigCheckItemsConfig
+type SigCheckItemsConfig struct {
+	Sig   string            `json:"sig"     required:"true"`
+	Items []CheckItemConfig `json:"items"   required:"true"`
 }
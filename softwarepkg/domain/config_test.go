@@ -0,0 +1,40 @@
+package domain
+
+import "testing"
+
+func TestDefaultConfig(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefault()
+
+	if len(cfg.CheckItems) == 0 {
+		t.Fatal("expect the built-in check items")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("the default config is invalid, err:%s", err.Error())
+	}
+}
+
+func TestMinNumApproved(t *testing.T) {
+	zero, negative := 0, -1
+
+	cfg := Config{MinNumApprovedByTC: &zero}
+	cfg.SetDefault()
+
+	if v := cfg.minNumApprovedByTC(); v != 0 {
+		t.Errorf("the explicit 0 should be kept, got %d", v)
+	}
+
+	if v := cfg.minNumApprovedBySigMaintainer(); v != defaultMinNumApprovedBySigMaintainer {
+		t.Errorf("expect the default when it is not set, got %d", v)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("the quorum of 0 should be valid, err:%s", err.Error())
+	}
+
+	cfg.MinNumApprovedBySigMaintainer = &negative
+	if err := cfg.Validate(); err == nil {
+		t.Error("expect an error for the negative quorum")
+	}
+}
@@ -25,3 +25,7 @@ func (v checkItemResult) CheckItemResult() string {
 func IsCheckItemPass(v CheckItemResult) bool {
 	return v != nil && v.CheckItemResult() == checkItemResultPass
 }
+
+func IsCheckItemNotPass(v CheckItemResult) bool {
+	return v != nil && v.CheckItemResult() == checkItemResultNotPass
+}
@@ -0,0 +1,43 @@
+package dp
+
+import "errors"
+
+const (
+	communityRoleTC            = "tc"
+	communityRoleCommitter     = "committer"
+	communityRoleSigMaintainer = "sig_maintainer"
+)
+
+var (
+	validCommunityRoles = map[string]bool{
+		communityRoleTC:            true,
+		communityRoleCommitter:     true,
+		communityRoleSigMaintainer: true,
+	}
+
+	CommunityRoleTC            = communityRole(communityRoleTC)
+	CommunityRoleCommitter     = communityRole(communityRoleCommitter)
+	CommunityRoleSigMaintainer = communityRole(communityRoleSigMaintainer)
+)
+
+type CommunityRole interface {
+	CommunityRole() string
+}
+
+func NewCommunityRole(v string) (CommunityRole, error) {
+	if !validCommunityRoles[v] {
+		return nil, errors.New("invalid community role")
+	}
+
+	return communityRole(v), nil
+}
+
+type communityRole string
+
+func (v communityRole) CommunityRole() string {
+	return string(v)
+}
+
+func IsSameCommunityRole(a, b CommunityRole) bool {
+	return a != nil && b != nil && a.CommunityRole() == b.CommunityRole()
+}
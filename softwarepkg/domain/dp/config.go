@@ -20,6 +20,9 @@ type Config struct {
 	MaxLengthOfPackageDesc       int      `json:"max_length_of_pkg_desc"`
 	MaxLengthOfReviewComment     int      `json:"max_length_of_review_comment"`
 	MaxLengthOfReasonToImportPkg int      `json:"max_length_of_reason_to_import_pkg"`
+
+	// NamePolicy is the rules which the name of a new pkg should follow.
+	NamePolicy PackageNamePolicy `json:"name_policy"`
 }
 
 func (cfg *Config) SetDefault() {
@@ -52,7 +55,7 @@ func (cfg *Config) Validate() error {
 		return errors.New("unkonw local platform")
 	}
 
-	return nil
+	return cfg.NamePolicy.validate()
 }
 
 func (cfg *Config) toLower(items []string) {
@@ -6,6 +6,10 @@ const (
 	packageOperationLogActionApprove = "approve"
 	packageOperationLogActionAbandon = "abandon"
 	packageOperationLogActionRerunci = "rerunci"
+	packageOperationLogActionRetire  = "retire"
+	packageOperationLogActionReopen  = "reopen"
+
+	packageOperationLogActionRequestChanges = "request_changes"
 )
 
 var (
@@ -14,6 +18,10 @@ var (
 	PackageOperationLogActionApprove = packageOperationLogAction(packageOperationLogActionApprove)
 	PackageOperationLogActionAbandon = packageOperationLogAction(packageOperationLogActionAbandon)
 	PackageOperationLogActionResunci = packageOperationLogAction(packageOperationLogActionRerunci)
+	PackageOperationLogActionRetire  = packageOperationLogAction(packageOperationLogActionRetire)
+	PackageOperationLogActionReopen  = packageOperationLogAction(packageOperationLogActionReopen)
+
+	PackageOperationLogActionRequestChanges = packageOperationLogAction(packageOperationLogActionRequestChanges)
 )
 
 type PackageOperationLogAction interface {
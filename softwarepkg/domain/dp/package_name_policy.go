@@ -0,0 +1,183 @@
+package dp
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/opensourceways/software-package-server/common/allerror"
+)
+
+// PackageNamePolicy is the rules which the name of a new pkg should follow.
+type PackageNamePolicy struct {
+	// AllowUppercase allows the uppercase letters in the name.
+	AllowUppercase bool `json:"allow_uppercase"`
+
+	// Reserved are the names which can't be applied, such as kernel.
+	Reserved []string `json:"reserved"`
+
+	// Blocked are the regular expressions of the names which can't be applied.
+	Blocked []string `json:"blocked"`
+
+	// PrefixRules are the naming conventions of the language ecosystems.
+	PrefixRules []PackageNamePrefixRule `json:"prefix_rules"`
+
+	blocked []*regexp.Regexp
+}
+
+func (p *PackageNamePolicy) validate() error {
+	p.blocked = make([]*regexp.Regexp, len(p.Blocked))
+
+	for i, v := range p.Blocked {
+		re, err := regexp.Compile(v)
+		if err != nil {
+			return fmt.Errorf("invalid blocked name: %s, err:%s", v, err.Error())
+		}
+
+		p.blocked[i] = re
+	}
+
+	for i := range p.PrefixRules {
+		if err := p.PrefixRules[i].validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PackageNamePrefixRule is the naming convention of a language ecosystem,
+// such as the pkg of python should be named as python-xxx.
+type PackageNamePrefixRule struct {
+	Ecosystem string `json:"ecosystem"  required:"true"`
+	Prefix    string `json:"prefix"     required:"true"`
+
+	// ForbiddenPrefixes are the prefixes which should be replaced by
+	// the Prefix, such as python3- and python2-.
+	ForbiddenPrefixes []string `json:"forbidden_prefixes"`
+
+	// UpstreamHosts are the hosts where the upstream of the ecosystem is,
+	// such as pypi.org. The pkg whose upstream is on them should be named
+	// with the Prefix.
+	UpstreamHosts []string `json:"upstream_hosts"`
+}
+
+func (r *PackageNamePrefixRule) validate() error {
+	if r.Ecosystem == "" || r.Prefix == "" {
+		return fmt.Errorf("missing ecosystem or prefix of the name prefix rule")
+	}
+
+	return nil
+}
+
+func (r *PackageNamePrefixRule) check(name string, upstream URL) error {
+	for _, v := range r.ForbiddenPrefixes {
+		if strings.HasPrefix(name, v) {
+			return allerror.New(
+				allerror.ErrorCodePkgNameWrongPrefix,
+				fmt.Sprintf(
+					"the pkg of %s should be named with prefix %s instead of %s, such as %s",
+					r.Ecosystem, r.Prefix, v, r.Prefix+strings.TrimPrefix(name, v),
+				),
+			)
+		}
+	}
+
+	if strings.HasPrefix(name, r.Prefix) || upstream == nil || !r.isUpstream(upstream) {
+		return nil
+	}
+
+	return allerror.New(
+		allerror.ErrorCodePkgNameMissingPrefix,
+		fmt.Sprintf(
+			"the upstream is of %s, so the pkg should be named with prefix %s, such as %s",
+			r.Ecosystem, r.Prefix, r.Prefix+name,
+		),
+	)
+}
+
+func (r *PackageNamePrefixRule) isUpstream(upstream URL) bool {
+	u, err := url.Parse(upstream.URL())
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	for _, v := range r.UpstreamHosts {
+		v = strings.ToLower(v)
+
+		if host == v || strings.HasSuffix(host, "."+v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckPackageNamePolicy checks whether the name of the new pkg follows the
+// naming conventions, and returns all the violations. The upstream decides
+// the ecosystem of the pkg.
+func CheckPackageNamePolicy(name PackageName, upstream URL) []error {
+	p := &config.NamePolicy
+	v := name.PackageName()
+
+	var r []error
+
+	if lower := strings.ToLower(v); !p.AllowUppercase && lower != v {
+		r = append(r, allerror.New(
+			allerror.ErrorCodePkgNameUppercase,
+			fmt.Sprintf("the name should be in lowercase, such as %s", lower),
+		))
+	}
+
+	for _, s := range p.Reserved {
+		if strings.EqualFold(s, v) {
+			r = append(r, allerror.New(
+				allerror.ErrorCodePkgNameReserved,
+				fmt.Sprintf("the name %s is reserved by the distribution", v),
+			))
+
+			break
+		}
+	}
+
+	for i, re := range p.blocked {
+		if re.MatchString(v) {
+			r = append(r, allerror.New(
+				allerror.ErrorCodePkgNameBlocked,
+				fmt.Sprintf("the name %s is blocked by the rule: %s", v, p.Blocked[i]),
+			))
+		}
+	}
+
+	for i := range p.PrefixRules {
+		if err := p.PrefixRules[i].check(v, upstream); err != nil {
+			r = append(r, err)
+		}
+	}
+
+	return r
+}
+
+// TrimEcosystemPrefix removes the prefix of language ecosystem from the name,
+// such as python- and python3-, so that the names of the same software can
+// be compared. The name should be in lowercase.
+func TrimEcosystemPrefix(name string) string {
+	longest := ""
+
+	for i := range config.NamePolicy.PrefixRules {
+		rule := &config.NamePolicy.PrefixRules[i]
+
+		for _, v := range append([]string{rule.Prefix}, rule.ForbiddenPrefixes...) {
+			v = strings.ToLower(v)
+
+			if len(v) > len(longest) && len(v) < len(name) && strings.HasPrefix(name, v) {
+				longest = v
+			}
+		}
+	}
+
+	return name[len(longest):]
+}
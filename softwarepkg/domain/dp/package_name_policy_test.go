@@ -0,0 +1,80 @@
+package dp
+
+import (
+	"testing"
+
+	"github.com/opensourceways/software-package-server/common/allerror"
+)
+
+func TestCheckPackageNamePolicy(t *testing.T) {
+	old := config
+	defer func() { config = old }()
+
+	config.NamePolicy = PackageNamePolicy{
+		Reserved: []string{"kernel"},
+		Blocked:  []string{"^test-"},
+		PrefixRules: []PackageNamePrefixRule{{
+			Ecosystem:         "python",
+			Prefix:            "python-",
+			ForbiddenPrefixes: []string{"python3-"},
+			UpstreamHosts:     []string{"pypi.org"},
+		}},
+	}
+
+	if err := config.NamePolicy.validate(); err != nil {
+		t.Fatalf("invalid name policy, err:%s", err.Error())
+	}
+
+	pypi := dpURL("https://files.pypi.org/foo")
+	other := dpURL("https://github.com/foo/foo")
+
+	cases := []struct {
+		name     string
+		upstream URL
+		want     []string
+	}{
+		{"foo", other, nil},
+		{"foo", nil, nil},
+		{"Foo", other, []string{allerror.ErrorCodePkgNameUppercase}},
+		{"kernel", other, []string{allerror.ErrorCodePkgNameReserved}},
+		{"Kernel", other, []string{
+			allerror.ErrorCodePkgNameUppercase, allerror.ErrorCodePkgNameReserved,
+		}},
+		{"test-foo", other, []string{allerror.ErrorCodePkgNameBlocked}},
+		{"python3-foo", other, []string{allerror.ErrorCodePkgNameWrongPrefix}},
+		{"foo", pypi, []string{allerror.ErrorCodePkgNameMissingPrefix}},
+		{"python-foo", pypi, nil},
+		{"test-Foo", pypi, []string{
+			allerror.ErrorCodePkgNameUppercase,
+			allerror.ErrorCodePkgNameBlocked,
+			allerror.ErrorCodePkgNameMissingPrefix,
+		}},
+	}
+
+	for _, c := range cases {
+		errs := CheckPackageNamePolicy(packageName(c.name), c.upstream)
+
+		if len(errs) != len(c.want) {
+			t.Errorf("%s: got %d violations, want %d", c.name, len(errs), len(c.want))
+
+			continue
+		}
+
+		for i, err := range errs {
+			code := ""
+			if v, ok := err.(interface{ ErrorCode() string }); ok {
+				code = v.ErrorCode()
+			}
+
+			if code != c.want[i] {
+				t.Errorf("%s: got %s, want %s", c.name, code, c.want[i])
+			}
+		}
+	}
+
+	config.NamePolicy.AllowUppercase = true
+
+	if errs := CheckPackageNamePolicy(packageName("Foo"), other); len(errs) != 0 {
+		t.Errorf("the uppercase should be allowed, got %v", errs)
+	}
+}
@@ -3,24 +3,33 @@ package dp
 import "errors"
 
 const (
-	packagePhaseCreatingRepo = "creating_repo"
-	packagePhaseReviewing    = "reviewing"
-	packagePhaseImported     = "imported"
-	packagePhaseClosed       = "closed"
+	packagePhaseCreatingRepo     = "creating_repo"
+	packagePhaseReviewing        = "reviewing"
+	packagePhaseChangesRequested = "changes_requested"
+	packagePhaseApproved         = "approved"
+	packagePhaseImported         = "imported"
+	packagePhaseClosed           = "closed"
+	packagePhaseRetired          = "retired"
 )
 
 var (
 	validPackagePhase = map[string]bool{
-		packagePhaseCreatingRepo: true,
-		packagePhaseReviewing:    true,
-		packagePhaseImported:     true,
-		packagePhaseClosed:       true,
+		packagePhaseCreatingRepo:     true,
+		packagePhaseReviewing:        true,
+		packagePhaseChangesRequested: true,
+		packagePhaseApproved:         true,
+		packagePhaseImported:         true,
+		packagePhaseClosed:           true,
+		packagePhaseRetired:          true,
 	}
 
-	PackagePhaseCreatingRepo = packagePhase(packagePhaseCreatingRepo)
-	PackagePhaseReviewing    = packagePhase(packagePhaseReviewing)
-	PackagePhaseImported     = packagePhase(packagePhaseImported)
-	PackagePhaseClosed       = packagePhase(packagePhaseClosed)
+	PackagePhaseCreatingRepo     = packagePhase(packagePhaseCreatingRepo)
+	PackagePhaseReviewing        = packagePhase(packagePhaseReviewing)
+	PackagePhaseChangesRequested = packagePhase(packagePhaseChangesRequested)
+	PackagePhaseApproved         = packagePhase(packagePhaseApproved)
+	PackagePhaseImported         = packagePhase(packagePhaseImported)
+	PackagePhaseClosed           = packagePhase(packagePhaseClosed)
+	PackagePhaseRetired          = packagePhase(packagePhaseRetired)
 )
 
 type PackagePhase interface {
@@ -28,6 +37,10 @@ type PackagePhase interface {
 	IsClosed() bool
 	IsReviewing() bool
 	IsCreatingRepo() bool
+	IsChangesRequested() bool
+	IsApproved() bool
+	IsRetired() bool
+	IsImported() bool
 }
 
 func NewPackagePhase(v string) (PackagePhase, error) {
@@ -55,3 +68,19 @@ func (v packagePhase) IsReviewing() bool {
 func (v packagePhase) IsCreatingRepo() bool {
 	return string(v) == packagePhaseCreatingRepo
 }
+
+func (v packagePhase) IsChangesRequested() bool {
+	return string(v) == packagePhaseChangesRequested
+}
+
+func (v packagePhase) IsApproved() bool {
+	return string(v) == packagePhaseApproved
+}
+
+func (v packagePhase) IsRetired() bool {
+	return string(v) == packagePhaseRetired
+}
+
+func (v packagePhase) IsImported() bool {
+	return string(v) == packagePhaseImported
+}
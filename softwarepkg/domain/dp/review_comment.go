@@ -4,20 +4,32 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/opensourceways/software-package-server/utils"
 )
 
 const (
-	cmdAPPROVE = "APPROVE"
+	cmdLGTM    = "LGTM"
+	cmdCheck   = "CHECK"
 	cmdReject  = "REJECT"
+	cmdAPPROVE = "APPROVE"
+	cmdAbandon = "ABANDON"
+	cmdRerunCI = "RERUN-CI"
+
+	checkItemPass = "pass"
+	checkItemFail = "fail"
 )
 
 var (
 	validCmds = map[string]bool{
-		cmdAPPROVE: true,
+		cmdLGTM:    true,
+		cmdCheck:   true,
 		cmdReject:  true,
+		cmdAPPROVE: true,
+		cmdAbandon: true,
+		cmdRerunCI: true,
 	}
 
 	commandRegex = regexp.MustCompile(`(?m)^/([^\s]+)[\t ]*([^\n\r]*)`)
@@ -25,7 +37,7 @@ var (
 
 type ReviewComment interface {
 	ReviewComment() string
-	ParseReviewComment() (isCmd, isApprove bool)
+	ParseReviewCommands() []ReviewCommand
 }
 
 func NewReviewComment(v string) (ReviewComment, error) {
@@ -48,35 +60,107 @@ func (v reviewComment) ReviewComment() string {
 	return string(v)
 }
 
-func (v reviewComment) ParseReviewComment() (isCmd, isApprove bool) {
-	if cmd := parseReviewCommand(string(v)); cmd != "" {
-		isCmd = true
-		isApprove = cmd == cmdAPPROVE
+func (v reviewComment) ParseReviewCommands() []ReviewCommand {
+	return parseReviewCommands(string(v))
+}
+
+func parseReviewCommands(comment string) (r []ReviewCommand) {
+	items := commandRegex.FindAllStringSubmatch(comment, -1)
+	for i := range items {
+		cmd := strings.ToUpper(items[i][1])
+		if !validCmds[cmd] {
+			continue
+		}
+
+		r = append(r, ReviewCommand{
+			cmd:  cmd,
+			args: strings.TrimSpace(items[i][2]),
+		})
 	}
 
 	return
 }
 
-func parseReviewCommand(comment string) string {
-	v := parseCommentCommands(comment)
-	n := len(v)
-	if n == 0 {
-		return ""
-	}
+// ReviewCommand
+type ReviewCommand struct {
+	cmd  string
+	args string
+}
 
-	for i := n - 1; i >= 0; i-- {
-		if validCmds[v[i]] {
-			return v[i]
-		}
+func (c *ReviewCommand) String() string {
+	s := "/" + strings.ToLower(c.cmd)
+	if c.args != "" {
+		s += " " + c.args
 	}
 
-	return ""
+	return s
 }
 
-func parseCommentCommands(comment string) (r []string) {
-	items := commandRegex.FindAllStringSubmatch(comment, -1)
-	for i := range items {
-		r = append(r, strings.ToUpper(items[i][1]))
+func (c *ReviewCommand) IsApprove() bool {
+	return c.cmd == cmdAPPROVE
+}
+
+func (c *ReviewCommand) IsReject() bool {
+	return c.cmd == cmdReject
+}
+
+func (c *ReviewCommand) IsAbandon() bool {
+	return c.cmd == cmdAbandon
+}
+
+func (c *ReviewCommand) IsRerunCI() bool {
+	return c.cmd == cmdRerunCI
+}
+
+// IsCheckItemReview returns true for /lgtm and /check
+func (c *ReviewCommand) IsCheckItemReview() bool {
+	return c.cmd == cmdLGTM || c.cmd == cmdCheck
+}
+
+// ParseCheckItemReview parses the arguments of the commands below.
+//
+//	/lgtm <index>
+//	/check <index> pass|fail <reason>
+func (c *ReviewCommand) ParseCheckItemReview() (index int, pass bool, reason string, err error) {
+	args := strings.Fields(c.args)
+	if len(args) == 0 {
+		err = errors.New("missing the index of check item")
+
+		return
+	}
+
+	if index, err = strconv.Atoi(args[0]); err != nil {
+		err = fmt.Errorf("invalid index of check item: %s", args[0])
+
+		return
+	}
+
+	if c.cmd == cmdLGTM {
+		pass = true
+
+		return
+	}
+
+	if len(args) < 2 {
+		err = errors.New("missing the result of check item, it should be pass or fail")
+
+		return
+	}
+
+	switch strings.ToLower(args[1]) {
+	case checkItemPass:
+		pass = true
+	case checkItemFail:
+	default:
+		err = fmt.Errorf("invalid result of check item: %s, it should be pass or fail", args[1])
+
+		return
+	}
+
+	reason = strings.Join(args[2:], " ")
+
+	if !pass && reason == "" {
+		err = errors.New("missing the reason why the check item is failed")
 	}
 
 	return
@@ -0,0 +1,80 @@
+package dp
+
+import "testing"
+
+func TestParseReviewCommands(t *testing.T) {
+	cases := []struct {
+		name    string
+		comment string
+		want    []string
+	}{
+		{"no command", "looks good to me", nil},
+		{"single command", "/lgtm 1", []string{"/lgtm 1"}},
+		{"case insensitive", "/LGTM 1", []string{"/lgtm 1"}},
+		{
+			"multiple commands", "/lgtm 1\n/check 2 fail bad spec\r\n/rerun-ci",
+			[]string{"/lgtm 1", "/check 2 fail bad spec", "/rerun-ci"},
+		},
+		{"unknown command", "/unknown 1\n/approve", []string{"/approve"}},
+		{"command not at the beginning of line", "please /approve", nil},
+		{"missing name of command", "/ lgtm 1", nil},
+		{"extra spaces of args", "/reject \t too old  ", []string{"/reject too old"}},
+	}
+
+	for _, c := range cases {
+		cmds := parseReviewCommands(c.comment)
+
+		if len(cmds) != len(c.want) {
+			t.Errorf("%s: got %d commands, want %d", c.name, len(cmds), len(c.want))
+
+			continue
+		}
+
+		for i := range cmds {
+			if s := cmds[i].String(); s != c.want[i] {
+				t.Errorf("%s: got %s, want %s", c.name, s, c.want[i])
+			}
+		}
+	}
+}
+
+func TestParseCheckItemReview(t *testing.T) {
+	cases := []struct {
+		name   string
+		cmd    ReviewCommand
+		index  int
+		pass   bool
+		reason string
+		err    bool
+	}{
+		{"lgtm", ReviewCommand{cmdLGTM, "2"}, 2, true, "", false},
+		{"check pass", ReviewCommand{cmdCheck, "3 PASS"}, 3, true, "", false},
+		{"check fail", ReviewCommand{cmdCheck, "3 fail no license"}, 3, false, "no license", false},
+		{"missing index", ReviewCommand{cmdLGTM, ""}, 0, false, "", true},
+		{"invalid index", ReviewCommand{cmdCheck, "x pass"}, 0, false, "", true},
+		{"missing result", ReviewCommand{cmdCheck, "3"}, 3, false, "", true},
+		{"invalid result", ReviewCommand{cmdCheck, "3 ok"}, 3, false, "", true},
+		{"fail without reason", ReviewCommand{cmdCheck, "3 fail"}, 3, false, "", true},
+	}
+
+	for _, c := range cases {
+		index, pass, reason, err := c.cmd.ParseCheckItemReview()
+
+		if (err != nil) != c.err {
+			t.Errorf("%s: got err %v, want err %v", c.name, err, c.err)
+
+			continue
+		}
+
+		if c.err {
+			continue
+		}
+
+		if index != c.index || pass != c.pass || reason != c.reason {
+			t.Errorf(
+				"%s: got (%d, %v, %s), want (%d, %v, %s)", c.name,
+				index, pass, reason, c.index, c.pass, c.reason,
+			)
+		}
+	}
+}
@@ -12,7 +12,15 @@ var (
 	errorNotTheImporter = errors.New("not the importer")
 )
 
+type errorCode interface {
+	ErrorCode() string
+}
+
 func ParseErrorCode(err error) string {
+	if v, ok := err.(errorCode); ok {
+		return v.ErrorCode()
+	}
+
 	if errors.Is(err, errorNotTheImporter) {
 		return codeSoftwarePkgNotImporter
 	}
@@ -23,3 +31,19 @@ func ParseErrorCode(err error) string {
 
 	return ""
 }
+
+// errorSkipped is the error of an event which doesn't match the pkg,
+// such as the one arriving late. Handling it again makes no difference.
+type errorSkipped struct {
+	error
+}
+
+func newErrorSkipped(msg string) errorSkipped {
+	return errorSkipped{errors.New(msg)}
+}
+
+// IsErrorSkipped returns true if the error means the event should be
+// ignored, including the one that can't be handled in current phase.
+func IsErrorSkipped(err error) bool {
+	return errors.As(err, new(errorSkipped)) || errors.As(err, new(errorIncorrectPhase))
+}
@@ -0,0 +1,18 @@
+package repository
+
+// OutboxEvent is an event which was saved with the pkg and is
+// waiting to be published.
+type OutboxEvent struct {
+	Id    string
+	PkgId string
+	Event string
+	Body  []byte
+}
+
+type SoftwarePkgOutbox interface {
+	// FindPendingEvents returns the unpublished events in the order
+	// they were saved.
+	FindPendingEvents(events []string, num int) ([]OutboxEvent, error)
+
+	MarkEventPublished(id string) error
+}
@@ -10,6 +10,7 @@ type OptToFindSoftwarePkgs struct {
 	PkgName  dp.PackageName
 	Platform dp.PackagePlatform
 	Importer dp.Account
+	CIStatus dp.PackageCIStatus
 
 	PageNum      int
 	CountPerPage int
@@ -27,6 +28,7 @@ type SoftwarePkg interface {
 	// AddSoftwarePkg adds a new pkg
 	AddSoftwarePkg(*domain.SoftwarePkgBasicInfo) error
 
+	// SaveSoftwarePkg saves the pkg with its changed reviews in a transaction.
 	SaveSoftwarePkg(pkg *domain.SoftwarePkgBasicInfo, version int) error
 
 	FindSoftwarePkgBasicInfo(pid string) (domain.SoftwarePkgBasicInfo, int, error)
@@ -35,11 +37,21 @@ type SoftwarePkg interface {
 
 	FindSoftwarePkgs(OptToFindSoftwarePkgs) (r []domain.SoftwarePkgBasicInfo, total int, err error)
 
-	AddReviewComment(pid string, comment *domain.SoftwarePkgReviewComment) error
+	// FindSoftwarePkgsByNames returns the pkgs whose names are exactly one of the names.
+	FindSoftwarePkgsByNames([]dp.PackageName) ([]domain.SoftwarePkgBasicInfo, error)
+
+	// FindSimilarSoftwarePkgCandidates returns the imported and in-flight pkgs
+	// whose name keys are near to or whose upstream key is same as the key.
+	// They should be filtered by domain.SimilarSoftwarePkgs.
+	FindSimilarSoftwarePkgCandidates(domain.SimilarityKey) ([]domain.SoftwarePkgBasicInfo, error)
+
+	// AddReviewComments adds the comments in order, either all of them are added or none of them.
+	AddReviewComments(pid string, comments []domain.SoftwarePkgReviewComment) error
 	FindReviewComment(pid, commentId string) (domain.SoftwarePkgReviewComment, error)
 
 	AddTranslatedReviewComment(pid string, comment *domain.SoftwarePkgTranslatedReviewComment) error
 	FindTranslatedReviewComment(*TranslatedReviewCommentIndex) (domain.SoftwarePkgTranslatedReviewComment, error)
 
-	AddOperationLog(*domain.SoftwarePkgOperationLog) error
+	// FindRevisions returns the revisions of the application of pkg in order.
+	FindRevisions(pid string) ([]domain.SoftwarePkgRevision, error)
 }
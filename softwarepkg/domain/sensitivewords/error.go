@@ -3,10 +3,18 @@ package sensitivewords
 // errorSensitiveInfo
 type errorSensitiveInfo struct {
 	error
+
+	segments []string
 }
 
-func NewErrorSensitiveInfo(err error) errorSensitiveInfo {
-	return errorSensitiveInfo{err}
+// NewErrorSensitiveInfo returns the error of sensitive info. The segments
+// are the parts of content which are sensitive, and they may be empty if
+// they are unknown.
+func NewErrorSensitiveInfo(err error, segments ...string) errorSensitiveInfo {
+	return errorSensitiveInfo{
+		error:    err,
+		segments: segments,
+	}
 }
 
 func IsErrorSensitiveInfo(err error) bool {
@@ -14,3 +22,12 @@ func IsErrorSensitiveInfo(err error) bool {
 
 	return ok
 }
+
+// SensitiveSegments returns the sensitive segments of the error of sensitive info.
+func SensitiveSegments(err error) []string {
+	if v, ok := err.(errorSensitiveInfo); ok {
+		return v.segments
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/pkgmanager"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/repository"
+)
+
+// maxConcurrentExistenceChecks is the max number of dependencies checked
+// against the distribution at the same time.
+const maxConcurrentExistenceChecks = 5
+
+// timeoutToResolveExistingDeps bounds the time to check the dependencies,
+// because it is done on the request of applying. The ones which are not
+// checked in time are regarded as not existing.
+const timeoutToResolveExistingDeps = 10 * time.Second
+
+type DependencyService interface {
+	// ResolveExistingDeps finds the dependencies of the spec which exist in
+	// the distribution in a limited time. It should be called before the
+	// spec is stored.
+	ResolveExistingDeps(*domain.SoftwarePkgSpec)
+
+	// AnalyzeDependencies resolves the dependencies of the pkg against the
+	// pkgs known to the server and the existing ones resolved before.
+	AnalyzeDependencies(*domain.SoftwarePkgBasicInfo) ([]domain.SoftwarePkgDependency, error)
+}
+
+func NewDependencyService(
+	repo repository.SoftwarePkg, manager pkgmanager.PkgManager,
+) DependencyService {
+	return &dependencyService{
+		repo:    repo,
+		manager: manager,
+	}
+}
+
+type dependencyService struct {
+	repo    repository.SoftwarePkg
+	manager pkgmanager.PkgManager
+}
+
+func (s *dependencyService) ResolveExistingDeps(spec *domain.SoftwarePkgSpec) {
+	names := spec.DependencyNames()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeoutToResolveExistingDeps)
+	defer cancel()
+
+	// the index of the existing dependency, or -1 if it doesn't exist.
+	// It is buffered, so the checks finished after the timeout never block.
+	checked := make(chan int, len(names))
+	limit := make(chan struct{}, maxConcurrentExistenceChecks)
+
+	go func() {
+		for i := range names {
+			select {
+			case limit <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			go func(i int) {
+				defer func() { <-limit }()
+
+				if s.manager.IsPkgExisted(names[i]) {
+					checked <- i
+				} else {
+					checked <- -1
+				}
+			}(i)
+		}
+	}()
+
+	existed := make([]bool, len(names))
+
+wait:
+	for n := 0; n < len(names); n++ {
+		select {
+		case i := <-checked:
+			if i >= 0 {
+				existed[i] = true
+			}
+
+		case <-ctx.Done():
+			logrus.Warnf(
+				"timeout to resolve the existing dependencies of %s, %d of %d are checked",
+				spec.Name, n, len(names),
+			)
+
+			break wait
+		}
+	}
+
+	spec.ExistingDeps = nil
+
+	for i := range names {
+		if existed[i] {
+			spec.ExistingDeps = append(spec.ExistingDeps, names[i].PackageName())
+		}
+	}
+}
+
+func (s *dependencyService) AnalyzeDependencies(pkg *domain.SoftwarePkgBasicInfo) (
+	[]domain.SoftwarePkgDependency, error,
+) {
+	deps := pkg.Spec.Dependencies()
+
+	names := pkg.Spec.DependencyNames()
+	if len(names) == 0 {
+		return deps, nil
+	}
+
+	pkgs, err := s.findPkgs(pkg.Id, names)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range deps {
+		if d := &deps[i]; d.Name != nil {
+			d.Resolve(pkgs[d.Name.PackageName()], pkg.Spec.IsExistingDep(d.Name))
+		}
+	}
+
+	return deps, nil
+}
+
+// findPkgs returns the imported or in-flight pkgs of the names. The imported
+// one is preferred if there are more than one pkgs of the same name.
+func (s *dependencyService) findPkgs(pid string, names []dp.PackageName) (
+	map[string]*domain.SoftwarePkgBasicInfo, error,
+) {
+	v, err := s.repo.FindSoftwarePkgsByNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	r := map[string]*domain.SoftwarePkgBasicInfo{}
+
+	for i := range v {
+		item := &v[i]
+
+		if item.Id == pid || item.Phase.IsClosed() || item.Phase.IsRetired() {
+			continue
+		}
+
+		name := item.PkgName.PackageName()
+
+		if old, ok := r[name]; !ok || !old.Phase.IsImported() {
+			r[name] = item
+		}
+	}
+
+	return r, nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/repository"
+)
+
+type SimilarityService interface {
+	// FindSimilarPkgs finds the imported and in-flight pkgs which may be
+	// the same software as the one of the name or the upstream.
+	FindSimilarPkgs(name dp.PackageName, upstream dp.URL) ([]domain.SimilarSoftwarePkg, error)
+}
+
+func NewSimilarityService(repo repository.SoftwarePkg) SimilarityService {
+	return &similarityService{repo: repo}
+}
+
+type similarityService struct {
+	repo repository.SoftwarePkg
+}
+
+func (s *similarityService) FindSimilarPkgs(name dp.PackageName, upstream dp.URL) (
+	[]domain.SimilarSoftwarePkg, error,
+) {
+	pkgs, err := s.repo.FindSimilarSoftwarePkgCandidates(domain.NewSimilarityKey(name, upstream))
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.SimilarSoftwarePkgs(name, upstream, pkgs), nil
+}
@@ -1,7 +1,6 @@
 package domain
 
 import (
-	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -12,8 +11,8 @@ import (
 )
 
 var (
-	notImporter    = allerror.New(allerror.ErrorCodeNotImporter, "not the importer")
-	incorrectPhase = allerror.New(allerror.ErrorCodeIncorrectPhase, "incorrect phase")
+	notImporter = allerror.New(allerror.ErrorCodeNotImporter, "not the importer")
+	ciNotPassed = allerror.New(allerror.ErrorCodeCINotPassed, "ci is not passed")
 )
 
 type User struct {
@@ -32,6 +31,10 @@ type SoftwarePkgApplication struct {
 	PackagePlatform   dp.PackagePlatform
 	ImportingPkgSig   dp.ImportingPkgSig
 	ReasonToImportPkg dp.ReasonToImportPkg
+
+	// License is the SPDX expression of the license. It is the one
+	// declared in the spec if it is not given.
+	License dp.License
 }
 
 type SoftwarePkgSourceCode struct {
@@ -42,16 +45,20 @@ type SoftwarePkgSourceCode struct {
 
 // SoftwarePkgCI
 type SoftwarePkgCI struct {
-	PRNum  int
-	Status dp.PackageCIStatus
-	// TODO deal with the case that the ci is timeout
-	//startTime int64
+	PRNum     int
+	Status    dp.PackageCIStatus
+	StartTime int64
 }
 
 func (ci *SoftwarePkgCI) isSuccess() bool {
 	return ci.Status != nil && ci.Status.IsCIPassed()
 }
 
+func (ci *SoftwarePkgCI) isTimeout() bool {
+	return ci.Status != nil && ci.Status.IsCIRunning() &&
+		ci.StartTime+config.CITimeout < utils.Now()
+}
+
 // SoftwarePkgApprover
 type SoftwarePkgApprover struct {
 	Account dp.Account
@@ -81,11 +88,34 @@ type SoftwarePkgBasicInfo struct {
 	Phase       dp.PackagePhase
 	CI          SoftwarePkgCI
 	AppliedAt   int64
+	ClosedAt    int64
 	Application SoftwarePkgApplication
+	Revision    int
+	Spec        SoftwarePkgSpec
 	RelevantPR  dp.URL
 	Review      SoftwarePkgReview
 	Logs        []SoftwarePkgOperationLog
 
+	// SrcRPMNotes are the findings of the src.rpm of current application.
+	// They are replaced each time the src.rpm is inspected.
+	SrcRPMNotes []string
+
+	// Events are the events which happened on the pkg and will be
+	// published after the pkg is saved.
+	Events []string
+
+	// NewComments are the comments which are added to the pkg by the
+	// operations on it and will be saved with the pkg.
+	NewComments []SoftwarePkgReviewComment
+
+	// NewRevisions are the revisions which are created by the operations
+	// on the pkg and will be saved with the pkg.
+	NewRevisions []SoftwarePkgRevision
+
+	// ReviewsChanged is true if the reviews are added or marked as stale by
+	// the operations on the pkg, and then they will be saved with the pkg.
+	ReviewsChanged bool
+
 	ApprovedBy []SoftwarePkgApprover
 	RejectedBy []SoftwarePkgApprover
 }
@@ -94,75 +124,226 @@ func (entity *SoftwarePkgBasicInfo) Sig() string {
 	return entity.Application.ImportingPkgSig.ImportingPkgSig()
 }
 
+// AddComment adds a comment which will be saved together with the pkg.
+func (entity *SoftwarePkgBasicInfo) AddComment(author dp.Account, content dp.ReviewComment) {
+	entity.NewComments = append(
+		entity.NewComments,
+		NewSoftwarePkgReviewComment(author, content),
+	)
+}
+
 func (entity *SoftwarePkgBasicInfo) CanAddReviewComment() bool {
-	return entity.Phase.IsReviewing()
+	return canDoInPhase(entity.Phase, PhaseActionComment)
 }
 
+// AddReview adds the review and moves the pkg to the approved phase if the
+// reviews pass, or to the changes_requested phase if any check item is not passed.
 func (entity *SoftwarePkgBasicInfo) AddReview(ur *UserReview) (bool, error) {
-	if !entity.Phase.IsReviewing() || !entity.CI.isSuccess() {
-		return false, errors.New("can't do this")
+	if err := entity.checkPhase(PhaseActionReview); err != nil {
+		return false, err
+	}
+
+	if !entity.CI.isSuccess() {
+		return false, ciNotPassed
+	}
+
+	entity.initCheckItems()
+
+	if err := entity.Review.validate(ur); err != nil {
+		return false, err
+	}
+
+	now := utils.Now()
+
+	for i := range ur.Items {
+		ur.Items[i].Revision = entity.Revision
+		ur.Items[i].ReviewedAt = now
 	}
 
 	entity.Review.add(ur)
+	entity.ReviewsChanged = true
 
-	b := entity.Review.pass()
-	if b {
-		entity.Phase = dp.PackagePhaseCreatingRepo
+	if entity.Review.pass() {
+		err := entity.changePhase(
+			ur.User, PhaseActionReview,
+			dp.PackageOperationLogActionApprove, dp.PackagePhaseApproved,
+		)
+		if err != nil {
+			return false, err
+		}
+
+		entity.addEvent(SoftwarePkgEventApproved)
+
+		return true, nil
 	}
 
-	return b, nil
-}
+	if !entity.Review.changesRequested() {
+		return false, entity.transit(PhaseActionReview, dp.PackagePhaseReviewing)
+	}
+
+	if entity.Phase.IsChangesRequested() {
+		return false, nil
+	}
 
-// TODO delete
-func (entity *SoftwarePkgBasicInfo) ApproveBy(user *SoftwarePkgApprover) (bool, error) {
-	return false, nil
+	return false, entity.changePhase(
+		ur.User, PhaseActionReview,
+		dp.PackageOperationLogActionRequestChanges, dp.PackagePhaseChangesRequested,
+	)
 }
 
-func (entity *SoftwarePkgBasicInfo) RejectBy(user *Reviewer) error {
-	if !entity.Phase.IsReviewing() {
-		return incorrectPhase
+// ApproveBy passes all the check items that the reviewer owns.
+func (entity *SoftwarePkgBasicInfo) ApproveBy(user *Reviewer) (UserReview, bool, error) {
+	entity.initCheckItems()
+
+	ur := UserReview{Reviewer: *user}
+
+	items := entity.Review.Items
+	for i := range items {
+		if items[i].isOwner(user.Role) {
+			ur.Items = append(ur.Items, CheckItemReview{
+				Index: items[i].Index,
+				Pass:  true,
+			})
+		}
 	}
 
-	if !user.isTC() {
-		return allerror.NewNoPermission("not tc")
+	if len(ur.Items) == 0 {
+		return ur, false, allerror.NewNoPermission("not the owner of any check item")
 	}
 
-	entity.Phase = dp.PackagePhaseClosed
+	approved, err := entity.AddReview(&ur)
+
+	return ur, approved, err
+}
 
+func (entity *SoftwarePkgBasicInfo) initCheckItems() {
+	if len(entity.Review.Items) == 0 {
+		// the pkg was applied before the check items were introduced
+		entity.Review.Items = CheckItems(
+			entity.Application.ImportingPkgSig,
+			entity.Application.PackagePlatform,
+		)
+	}
+}
+
+func (entity *SoftwarePkgBasicInfo) addLog(
+	user dp.Account, action dp.PackageOperationLogAction,
+	details *SoftwarePkgOperationLogDetails,
+) {
 	entity.Logs = append(
 		entity.Logs,
-		NewSoftwarePkgOperationLog(
-			user.User, dp.PackageOperationLogActionReject, entity.Id,
-		),
+		NewSoftwarePkgOperationLog(user, action, entity.Id, details),
 	)
+}
+
+// changePhase moves the pkg to the phase by the action and records it.
+func (entity *SoftwarePkgBasicInfo) changePhase(
+	user dp.Account, action string,
+	logAction dp.PackageOperationLogAction, phase dp.PackagePhase,
+) error {
+	old := entity.Phase
+
+	if err := entity.transit(action, phase); err != nil {
+		return err
+	}
+
+	entity.addLog(user, logAction, &SoftwarePkgOperationLogDetails{
+		OldPhase: old,
+		NewPhase: phase,
+	})
 
 	return nil
 }
 
+func (entity *SoftwarePkgBasicInfo) RejectBy(user *Reviewer) error {
+	if err := entity.checkPhase(PhaseActionReject); err != nil {
+		return err
+	}
+
+	if !user.isTC() {
+		return allerror.NewNoPermission("not tc")
+	}
+
+	return entity.changePhase(
+		user.User, PhaseActionReject,
+		dp.PackageOperationLogActionReject, dp.PackagePhaseClosed,
+	)
+}
+
 func (entity *SoftwarePkgBasicInfo) Abandon(user *User) error {
-	if !entity.Phase.IsReviewing() {
-		return incorrectPhase
+	if err := entity.checkPhase(PhaseActionAbandon); err != nil {
+		return err
 	}
 
 	if !dp.IsSameAccount(user.Account, entity.Importer.Account) {
 		return notImporter
 	}
 
-	entity.Phase = dp.PackagePhaseClosed
+	return entity.changePhase(
+		user.Account, PhaseActionAbandon,
+		dp.PackageOperationLogActionAbandon, dp.PackagePhaseClosed,
+	)
+}
+
+// RetireBy retires the imported pkg. Only the tc can do it.
+func (entity *SoftwarePkgBasicInfo) RetireBy(user *Reviewer) error {
+	if err := entity.checkPhase(PhaseActionRetire); err != nil {
+		return err
+	}
+
+	if !user.isTC() {
+		return allerror.NewNoPermission("not tc")
+	}
 
-	entity.Logs = append(
-		entity.Logs,
-		NewSoftwarePkgOperationLog(
-			user.Account, dp.PackageOperationLogActionAbandon, entity.Id,
-		),
+	return entity.changePhase(
+		user.User, PhaseActionRetire,
+		dp.PackageOperationLogActionRetire, dp.PackagePhaseRetired,
 	)
+}
+
+// ReopenBy reopens the closed pkg. The importer can reopen it within the
+// reopen window after it was closed, and the tc can reopen it at any time.
+// The ci will rerun, and the previous reviews are kept but become stale.
+func (entity *SoftwarePkgBasicInfo) ReopenBy(user *Reviewer) error {
+	if err := entity.checkPhase(PhaseActionReopen); err != nil {
+		return err
+	}
+
+	if !user.isTC() {
+		if !dp.IsSameAccount(user.User, entity.Importer.Account) {
+			return allerror.NewNoPermission("neither the importer nor tc")
+		}
+
+		// the time is unknown if the pkg was closed before it is recorded.
+		if entity.ClosedAt > 0 && entity.ClosedAt+config.ReopenWindow < utils.Now() {
+			return allerror.New(
+				allerror.ErrorCodeReopenExpired,
+				"it is too late to reopen, please contact the tc",
+			)
+		}
+	}
+
+	err := entity.changePhase(
+		user.User, PhaseActionReopen,
+		dp.PackageOperationLogActionReopen, dp.PackagePhaseReviewing,
+	)
+	if err != nil {
+		return err
+	}
+
+	entity.ClosedAt = 0
+	entity.CI = SoftwarePkgCI{Status: dp.PackageCIStatusWaiting}
+	entity.Review.markStale(func(*CheckItem) bool { return true })
+	entity.ReviewsChanged = true
+
+	entity.addEvent(SoftwarePkgEventToRerunCI)
 
 	return nil
 }
 
 func (entity *SoftwarePkgBasicInfo) RerunCI(user *User) (bool, error) {
-	if !entity.Phase.IsReviewing() {
-		return false, incorrectPhase
+	if err := entity.checkPhase(PhaseActionRerunCI); err != nil {
+		return false, err
 	}
 
 	if entity.CI.Status.IsCIRunning() {
@@ -177,47 +358,125 @@ func (entity *SoftwarePkgBasicInfo) RerunCI(user *User) (bool, error) {
 		return false, nil
 	}
 
+	entity.addLog(
+		user.Account, dp.PackageOperationLogActionResunci,
+		&SoftwarePkgOperationLogDetails{
+			CIPRNum: entity.CI.PRNum,
+			Reason:  "the status of last ci is " + entity.CI.Status.PackageCIStatus(),
+		},
+	)
+
 	entity.CI = SoftwarePkgCI{Status: dp.PackageCIStatusWaiting}
 
-	entity.Logs = append(
-		entity.Logs,
-		NewSoftwarePkgOperationLog(
-			user.Account, dp.PackageOperationLogActionResunci, entity.Id,
-		),
-	)
+	entity.addEvent(SoftwarePkgEventToRerunCI)
 
 	return true, nil
 }
 
-func (entity *SoftwarePkgBasicInfo) UpdateApplication(cmd *SoftwarePkgApplication, user *User) error {
-	if !entity.Phase.IsReviewing() {
-		return errors.New("can't do this")
+// UpdateApplication updates the application and the spec parsed from its spec url.
+func (entity *SoftwarePkgBasicInfo) UpdateApplication(
+	cmd *SoftwarePkgApplication, spec *SoftwarePkgSpec, user *User,
+) error {
+	if err := entity.checkPhase(PhaseActionUpdate); err != nil {
+		return err
 	}
 
 	if !dp.IsSameAccount(user.Account, entity.Importer.Account) {
 		return notImporter
 	}
 
+	if err := entity.setSpec(spec); err != nil {
+		return err
+	}
+
+	cmd.fillLicense(spec)
+
+	changed := changedFields(&entity.Application, cmd)
+	if len(changed) == 0 {
+		return nil
+	}
+
+	sourceCodeChanged := isSourceCodeChanged(changed)
+
+	// the result of running ci would be for the old source code.
+	if sourceCodeChanged && entity.CI.Status.IsCIRunning() {
+		return allerror.New(allerror.ErrorCodeCIIsRunning, "ci is running")
+	}
+
+	if entity.Revision == 0 {
+		// the pkg was applied before the revisions were introduced,
+		// so snapshot the original application first.
+		entity.addRevision(entity.Importer.Account)
+		entity.NewRevisions[0].CreatedAt = entity.AppliedAt
+	}
+
+	app := &entity.Application
+	if app.ImportingPkgSig.ImportingPkgSig() != cmd.ImportingPkgSig.ImportingPkgSig() ||
+		!dp.IsSamePlatform(app.PackagePlatform, cmd.PackagePlatform) {
+		entity.Review.Items = CheckItems(cmd.ImportingPkgSig, cmd.PackagePlatform)
+	}
+
+	// the findings are about the old src.rpm.
+	if containsField(changed, ApplicationFieldSrcRPMURL) {
+		entity.SrcRPMNotes = nil
+	}
+
 	entity.Application = *cmd
 
-	return nil
+	// the review of tc which overrides the result of checking the
+	// license doesn't count if the license is changed.
+	licenseChanged := containsField(changed, ApplicationFieldLicense)
+
+	entity.Review.markStale(func(item *CheckItem) bool {
+		return item.isAffectedBy(changed) ||
+			(licenseChanged && entity.Review.isLicenseItem(item))
+	})
+	entity.ReviewsChanged = true
+
+	entity.checkLicense()
+
+	// the waiting ci will test the new source code, so only rerun the finished one.
+	if sourceCodeChanged && !entity.CI.Status.IsCIWaiting() {
+		entity.CI = SoftwarePkgCI{Status: dp.PackageCIStatusWaiting}
+
+		entity.addEvent(SoftwarePkgEventToRerunCI)
+	}
+
+	entity.addRevision(user.Account)
+
+	// it is back to reviewing if the reviews requesting changes become stale.
+	phase := dp.PackagePhaseReviewing
+	if entity.Review.changesRequested() {
+		phase = dp.PackagePhaseChangesRequested
+	}
+
+	return entity.changePhase(
+		user.Account, PhaseActionUpdate, dp.PackageOperationLogActionUpdate, phase,
+	)
 }
 
 func (entity *SoftwarePkgBasicInfo) HandleCIChecking() error {
-	b := entity.Phase.IsReviewing() && entity.CI.Status.IsCIWaiting()
-	if !b {
-		return errors.New("can't do this")
+	if err := entity.checkPhase(phaseActionHandleCI); err != nil {
+		return err
+	}
+
+	if !entity.CI.Status.IsCIWaiting() {
+		return newErrorSkipped("ci is not waiting")
 	}
 
 	entity.CI.Status = dp.PackageCIStatusRunning
-	//entity.CI.startTime = utils.Now()
+	entity.CI.StartTime = utils.Now()
 
 	return nil
 }
 
 func (entity *SoftwarePkgBasicInfo) HandleCIChecked(success bool, prNum int) error {
-	if !entity.Phase.IsReviewing() || entity.CI.PRNum != prNum {
-		return errors.New("can't do this")
+	if err := entity.checkPhase(phaseActionHandleCI); err != nil {
+		return err
+	}
+
+	if !entity.CI.Status.IsCIRunning() || entity.CI.PRNum != prNum {
+		return newErrorSkipped("ci is not running or the pr is not matched")
 	}
 
 	if success {
@@ -230,53 +489,79 @@ func (entity *SoftwarePkgBasicInfo) HandleCIChecked(success bool, prNum int) err
 	return nil
 }
 
-func (entity *SoftwarePkgBasicInfo) HandlePkgInitialized(pr dp.URL) error {
-	if !entity.Phase.IsCreatingRepo() {
-		return errors.New("can't do this")
+// HandleCITimeout marks the ci as timeout if it has run too long. The start
+// time of the ci which was running before it is recorded is unknown, so it
+// is set now and the ci will be timeout later.
+// It returns whether the ci is timeout and whether the pkg is changed.
+func (entity *SoftwarePkgBasicInfo) HandleCITimeout() (timeout, changed bool) {
+	ci := &entity.CI
+
+	if !canDoInPhase(entity.Phase, phaseActionHandleCI) || ci.Status == nil || !ci.Status.IsCIRunning() {
+		return
 	}
 
-	entity.RelevantPR = pr
+	if ci.StartTime == 0 {
+		ci.StartTime = utils.Now()
 
-	return nil
+		return false, true
+	}
+
+	if !ci.isTimeout() {
+		return
+	}
+
+	ci.Status = dp.PackageCIStatusTimeout
+
+	return true, true
 }
 
-func (entity *SoftwarePkgBasicInfo) HandlePkgAlreadyExisted() error {
-	if !entity.Phase.IsCreatingRepo() {
-		return errors.New("can't do this")
+func (entity *SoftwarePkgBasicInfo) HandlePkgInitialized(pr dp.URL) error {
+	if err := entity.transit(phaseActionInitialize, dp.PackagePhaseCreatingRepo); err != nil {
+		return err
 	}
 
-	entity.Phase = dp.PackagePhaseClosed
+	entity.RelevantPR = pr
+
+	if !entity.Application.PackagePlatform.IsLocalPlatform() {
+		entity.addEvent(SoftwarePkgEventIndirectlyApproved)
+	}
 
 	return nil
 }
 
+func (entity *SoftwarePkgBasicInfo) HandlePkgAlreadyExisted() error {
+	return entity.transit(phaseActionHandleExisted, dp.PackagePhaseClosed)
+}
+
 type RepoCreatedInfo struct {
 	Platform dp.PackagePlatform
 	RepoLink dp.URL
 }
 
 func (entity *SoftwarePkgBasicInfo) HandleRepoCreated(info RepoCreatedInfo) error {
-	if !entity.Phase.IsCreatingRepo() {
-		return errors.New("can't do this")
+	if err := entity.checkPhase(phaseActionCreateRepo); err != nil {
+		return err
 	}
 
 	if !dp.IsSamePlatform(entity.Application.PackagePlatform, info.Platform) {
-		return errors.New("ignore unmached platform")
+		return newErrorSkipped("ignore unmached platform")
 	}
 
 	entity.RepoLink = info.RepoLink
 
-	return nil
+	return entity.transit(phaseActionCreateRepo, dp.PackagePhaseCreatingRepo)
 }
 
 func (entity *SoftwarePkgBasicInfo) HandleCodeSaved(info RepoCreatedInfo) error {
-	if err := entity.HandleRepoCreated(info); err != nil {
+	if err := entity.checkPhase(phaseActionHandleCodeSave); err != nil {
 		return err
 	}
 
-	entity.Phase = dp.PackagePhaseImported
+	if err := entity.HandleRepoCreated(info); err != nil {
+		return err
+	}
 
-	return nil
+	return entity.transit(phaseActionHandleCodeSave, dp.PackagePhaseImported)
 }
 
 // SoftwarePkg
@@ -287,13 +572,40 @@ type SoftwarePkg struct {
 	Comments []SoftwarePkgReviewComment
 }
 
-func NewSoftwarePkg(user *User, name dp.PackageName, app *SoftwarePkgApplication) SoftwarePkgBasicInfo {
-	return SoftwarePkgBasicInfo{
+// NewSoftwarePkg news a pkg with the spec parsed from the spec url of application.
+func NewSoftwarePkg(
+	user *User, name dp.PackageName, app *SoftwarePkgApplication, spec *SoftwarePkgSpec,
+) (SoftwarePkgBasicInfo, error) {
+	// the first violation is returned, and all of them can be found by
+	// validating the application.
+	if errs := dp.CheckPackageNamePolicy(name, app.SourceCode.Upstream); len(errs) > 0 {
+		return SoftwarePkgBasicInfo{}, errs[0]
+	}
+
+	app.fillLicense(spec)
+
+	v := SoftwarePkgBasicInfo{
 		PkgName:     name,
 		Importer:    *user,
 		Phase:       dp.PackagePhaseReviewing,
 		CI:          SoftwarePkgCI{Status: dp.PackageCIStatusWaiting},
 		Application: *app,
 		AppliedAt:   utils.Now(),
+		Events:      []string{SoftwarePkgEventApplied},
+		Review: SoftwarePkgReview{
+			// snapshot the check items, so that the later changes of
+			// the config will not affect the pkg.
+			Items: CheckItems(app.ImportingPkgSig, app.PackagePlatform),
+		},
 	}
+
+	if err := v.setSpec(spec); err != nil {
+		return v, err
+	}
+
+	v.checkLicense()
+
+	v.addRevision(user.Account)
+
+	return v, nil
 }
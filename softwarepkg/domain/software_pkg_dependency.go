@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"strings"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+// The kinds of dependency.
+const (
+	DependencyKindBuild   = "build"
+	DependencyKindRuntime = "runtime"
+)
+
+// The status of dependency.
+const (
+	// DependencyStatusExisted means the dependency exists in the distribution.
+	DependencyStatusExisted = "existed"
+
+	// DependencyStatusImported means the dependency was imported by the application.
+	DependencyStatusImported = "imported"
+
+	// DependencyStatusInFlight means the application of the dependency is in progress.
+	DependencyStatusInFlight = "in_flight"
+
+	// DependencyStatusMissing means the dependency is not found anywhere.
+	DependencyStatusMissing = "missing"
+
+	// DependencyStatusUnresolved means the dependency can't be resolved by
+	// the name, such as pkgconfig(glib-2.0) and /usr/bin/python3.
+	DependencyStatusUnresolved = "unresolved"
+)
+
+// SoftwarePkgDependency is a dependency declared by BuildRequires or Requires in the spec.
+type SoftwarePkgDependency struct {
+	Kind string
+
+	// Requirement is the one declared in the spec, such as "gcc >= 8.0".
+	Requirement string
+
+	// Name is nil if the dependency is unresolved.
+	Name   dp.PackageName
+	Status string
+
+	// PkgId is the id of the imported pkg or the in-flight application.
+	PkgId string
+}
+
+// IsMissing returns true if the dependency is not available in the distribution yet.
+func (d *SoftwarePkgDependency) IsMissing() bool {
+	return d.Status == DependencyStatusMissing || d.Status == DependencyStatusInFlight
+}
+
+// Resolve sets the status of dependency by the pkg of the same name, which is
+// the one imported or applied. It is existed if found in the distribution.
+func (d *SoftwarePkgDependency) Resolve(pkg *SoftwarePkgBasicInfo, existed bool) {
+	switch {
+	case pkg != nil && pkg.Phase.IsImported():
+		d.Status = DependencyStatusImported
+		d.PkgId = pkg.Id
+
+	case existed:
+		d.Status = DependencyStatusExisted
+
+	case pkg != nil:
+		d.Status = DependencyStatusInFlight
+		d.PkgId = pkg.Id
+
+	default:
+		d.Status = DependencyStatusMissing
+	}
+}
+
+// Dependencies returns the dependencies declared in the spec. The ones which
+// are provided by the pkg itself, such as its sub packages, are excluded.
+func (spec *SoftwarePkgSpec) Dependencies() []SoftwarePkgDependency {
+	var r []SoftwarePkgDependency
+
+	seen := map[string]bool{}
+
+	add := func(kind string, requirements []string) {
+		for _, req := range requirements {
+			if seen[kind+req] {
+				continue
+			}
+			seen[kind+req] = true
+
+			d := SoftwarePkgDependency{
+				Kind:        kind,
+				Requirement: req,
+			}
+
+			if d.Name = dependencyName(req); d.Name == nil {
+				d.Status = DependencyStatusUnresolved
+			} else if spec.provides(d.Name) {
+				continue
+			}
+
+			r = append(r, d)
+		}
+	}
+
+	add(DependencyKindBuild, spec.BuildRequires)
+	add(DependencyKindRuntime, spec.Requires)
+
+	return r
+}
+
+// DependencyNames returns the names of the dependencies which can be resolved.
+func (spec *SoftwarePkgSpec) DependencyNames() []dp.PackageName {
+	deps := spec.Dependencies()
+
+	names := make([]dp.PackageName, 0, len(deps))
+	seen := map[string]bool{}
+
+	for i := range deps {
+		if name := deps[i].Name; name != nil && !seen[name.PackageName()] {
+			seen[name.PackageName()] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// provides returns true if the pkg itself or one of its sub packages is the name.
+func (spec *SoftwarePkgSpec) provides(name dp.PackageName) bool {
+	v := name.PackageName()
+	if v == spec.Name {
+		return true
+	}
+
+	for _, item := range spec.SubPackages {
+		if item == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsExistingDep returns true if the dependency exists in the distribution.
+func (spec *SoftwarePkgSpec) IsExistingDep(name dp.PackageName) bool {
+	v := name.PackageName()
+
+	for _, item := range spec.ExistingDeps {
+		if item == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dependencyName returns the name of pkg in the requirement. It returns nil
+// if the requirement is a virtual one which can't be resolved by the name.
+func dependencyName(requirement string) dp.PackageName {
+	items := strings.Fields(requirement)
+	if len(items) == 0 {
+		return nil
+	}
+
+	if v := items[0]; strings.ContainsAny(v, "()/%") {
+		return nil
+	}
+
+	name, err := dp.NewPackageName(items[0])
+	if err != nil {
+		return nil
+	}
+
+	return name
+}
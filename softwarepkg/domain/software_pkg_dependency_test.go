@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+func TestDependencies(t *testing.T) {
+	dp.Init(&dp.Config{MaxLengthOfPackageName: 64}, nil)
+
+	spec := SoftwarePkgSpec{
+		Name: "perl",
+		BuildRequires: []string{
+			"gcc", "perl", "perl-Test-Simple >= 1.0", "pkgconfig(glib-2.0)", "gcc",
+		},
+		Requires:    []string{"perl-libs", "gcc-c++", "perl-devel"},
+		SubPackages: []string{"perl-libs", "perl-devel"},
+	}
+
+	var got []string
+	for _, d := range spec.Dependencies() {
+		got = append(got, d.Kind+":"+d.Requirement+":"+d.Status)
+	}
+
+	want := []string{
+		"build:gcc:",
+		"build:perl-Test-Simple >= 1.0:",
+		"build:pkgconfig(glib-2.0):" + DependencyStatusUnresolved,
+		"runtime:gcc-c++:" + DependencyStatusUnresolved,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	var names []string
+	for _, n := range spec.DependencyNames() {
+		names = append(names, n.PackageName())
+	}
+
+	if want := []string{"gcc", "perl-Test-Simple"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got names %v, want %v", names, want)
+	}
+}
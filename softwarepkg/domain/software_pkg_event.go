@@ -2,18 +2,66 @@ package domain
 
 import (
 	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
 
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
 )
 
+const (
+	SoftwarePkgEventApplied            = "applied"
+	SoftwarePkgEventToRerunCI          = "to_rerun_ci"
+	SoftwarePkgEventApproved           = "approved"
+	SoftwarePkgEventIndirectlyApproved = "indirectly_approved"
+)
+
 var (
 	NewSoftwarePkgAppUpdatedEvent  = NewSoftwarePkgAppliedEvent
 	NewSoftwarePkgInitializedEvent = NewSoftwarePkgApprovedEvent
 )
 
+// EventMessage returns the message of the event which happened on the pkg.
+func (entity *SoftwarePkgBasicInfo) EventMessage(event string) ([]byte, error) {
+	switch event {
+	case SoftwarePkgEventApplied:
+		e := NewSoftwarePkgAppliedEvent(entity)
+
+		return e.Message()
+
+	case SoftwarePkgEventToRerunCI:
+		e := NewSoftwarePkgAppUpdatedEvent(entity)
+
+		return e.Message()
+
+	case SoftwarePkgEventApproved:
+		e := NewSoftwarePkgApprovedEvent(entity)
+
+		return e.Message()
+
+	case SoftwarePkgEventIndirectlyApproved:
+		e := NewSoftwarePkgInitializedEvent(entity)
+
+		return e.Message()
+	}
+
+	return nil, fmt.Errorf("unknown event: %s", event)
+}
+
+func (entity *SoftwarePkgBasicInfo) addEvent(event string) {
+	entity.Events = append(entity.Events, event)
+}
+
+// newEventId generates the id of event. The consumers can use it
+// to detect the duplicate messages.
+func newEventId() string {
+	return uuid.NewString()
+}
+
 // softwarePkgAppliedEvent
 type softwarePkgAppliedEvent struct {
-	PkgId string `json:"pkg_id"`
+	EventId string `json:"event_id"`
+	PkgId   string `json:"pkg_id"`
 }
 
 func (e *softwarePkgAppliedEvent) Message() ([]byte, error) {
@@ -22,7 +70,8 @@ func (e *softwarePkgAppliedEvent) Message() ([]byte, error) {
 
 func NewSoftwarePkgAppliedEvent(pkg *SoftwarePkgBasicInfo) softwarePkgAppliedEvent {
 	return softwarePkgAppliedEvent{
-		PkgId: pkg.Id,
+		EventId: newEventId(),
+		PkgId:   pkg.Id,
 	}
 }
 
@@ -34,6 +83,7 @@ func UnmarshalToSoftwarePkgAppliedEvent(data []byte) (e softwarePkgAppliedEvent,
 
 // softwarePkgApprovedEvent
 type softwarePkgApprovedEvent struct {
+	EventId           string `json:"event_id"`
 	Importer          string `json:"importer"`
 	ImporterEmail     string `json:"importer_email"`
 	PkgId             string `json:"pkg_id"`
@@ -56,6 +106,7 @@ func NewSoftwarePkgApprovedEvent(pkg *SoftwarePkgBasicInfo) softwarePkgApprovedE
 	app := &pkg.Application
 
 	return softwarePkgApprovedEvent{
+		EventId:           newEventId(),
 		Importer:          pkg.Importer.Account.Account(),
 		ImporterEmail:     pkg.Importer.Email.Email(),
 		PkgId:             pkg.Id,
@@ -73,6 +124,7 @@ func NewSoftwarePkgApprovedEvent(pkg *SoftwarePkgBasicInfo) softwarePkgApprovedE
 
 // softwarePkgAlreadyExistedEvent
 type softwarePkgAlreadyExistedEvent struct {
+	EventId string `json:"event_id"`
 	PkgName string `json:"pkg_name"`
 }
 
@@ -82,6 +134,7 @@ func (e *softwarePkgAlreadyExistedEvent) Message() ([]byte, error) {
 
 func NewSoftwarePkgAlreadyExistEvent(pkg dp.PackageName) softwarePkgAlreadyExistedEvent {
 	return softwarePkgAlreadyExistedEvent{
+		EventId: newEventId(),
 		PkgName: pkg.PackageName(),
 	}
 }
@@ -0,0 +1,303 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+// licenseStatus is the status of a license checked against the allow-list
+// and deny-list. The larger, the worse.
+type licenseStatus int
+
+const (
+	licenseAllowed licenseStatus = iota
+	licenseUnknown
+	licenseDenied
+)
+
+// LicenseCheck is the result of checking the license of pkg automatically.
+// It answers the check item about the license.
+type LicenseCheck struct {
+	// Index is the index of the check item about the license.
+	Index     int
+	License   string
+	Compliant bool
+
+	// Reason explains why the license is not compliant.
+	Reason string
+}
+
+// LicenseConfig
+type LicenseConfig struct {
+	// CheckItem is the index of the check item about the license,
+	// which will be answered by checking the license automatically.
+	CheckItem int `json:"check_item"`
+
+	// Allowed are the SPDX ids of the licenses which can be imported,
+	// such as MIT and Apache-2.0. The license will not be checked if empty.
+	Allowed []string `json:"allowed"`
+
+	// Denied are the SPDX ids of the licenses which can't be imported.
+	// The license which is neither allowed nor denied is unknown.
+	Denied []string `json:"denied"`
+}
+
+func (cfg *LicenseConfig) enabled() bool {
+	return len(cfg.Allowed) > 0
+}
+
+func (cfg *LicenseConfig) status(id string) licenseStatus {
+	for _, v := range cfg.Denied {
+		if strings.EqualFold(v, id) {
+			return licenseDenied
+		}
+	}
+
+	for _, v := range cfg.Allowed {
+		if strings.EqualFold(v, id) {
+			return licenseAllowed
+		}
+	}
+
+	return licenseUnknown
+}
+
+// check checks the SPDX expression of license, such as "MIT OR (GPL-2.0 WITH
+// Classpath-exception-2.0)". It returns the reason if it is not compliant.
+func (cfg *LicenseConfig) check(license string) (bool, string) {
+	p := licenseParser{
+		cfg:    cfg,
+		tokens: tokenizeLicense(license),
+	}
+
+	status, err := p.parse()
+	if err != nil {
+		return false, err.Error()
+	}
+
+	switch status {
+	case licenseDenied:
+		return false, "denied license: " + strings.Join(p.denied, ", ")
+
+	case licenseUnknown:
+		return false, "unknown license: " + strings.Join(p.unknown, ", ")
+	}
+
+	return true, ""
+}
+
+// tokenizeLicense splits the expression into the parentheses, the operators
+// and the ids. The adjacent words are joined as an id, because the license
+// in the spec may be the legacy name, such as "ASL 2.0".
+func tokenizeLicense(s string) []string {
+	s = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(s)
+
+	var (
+		r     []string
+		words []string
+	)
+
+	flush := func() {
+		if len(words) > 0 {
+			r = append(r, strings.Join(words, " "))
+			words = nil
+		}
+	}
+
+	for _, w := range strings.Fields(s) {
+		switch strings.ToUpper(w) {
+		case "(", ")", "AND", "OR", "WITH":
+			flush()
+			r = append(r, strings.ToUpper(w))
+
+		default:
+			words = append(words, w)
+		}
+	}
+
+	flush()
+
+	return r
+}
+
+// licenseParser evaluates the SPDX expression by the grammar below.
+//
+//	expr := and-expr { "OR" and-expr }
+//	and-expr := with-expr { "AND" with-expr }
+//	with-expr := atom [ "WITH" id ]
+//	atom := id | "(" expr ")"
+type licenseParser struct {
+	cfg     *LicenseConfig
+	tokens  []string
+	pos     int
+	unknown []string
+	denied  []string
+}
+
+func (p *licenseParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+
+	return ""
+}
+
+func (p *licenseParser) parse() (licenseStatus, error) {
+	if len(p.tokens) == 0 {
+		return licenseUnknown, errors.New("empty license")
+	}
+
+	status, err := p.parseExpr()
+	if err != nil {
+		return status, err
+	}
+
+	if p.pos < len(p.tokens) {
+		return status, fmt.Errorf("invalid license expression, unexpected %s", p.peek())
+	}
+
+	return status, nil
+}
+
+// parseExpr is compliant if any of the licenses is compliant.
+func (p *licenseParser) parseExpr() (licenseStatus, error) {
+	status, err := p.parseAndExpr()
+
+	for err == nil && p.peek() == "OR" {
+		p.pos++
+
+		var v licenseStatus
+		if v, err = p.parseAndExpr(); v < status {
+			status = v
+		}
+	}
+
+	return status, err
+}
+
+// parseAndExpr is compliant if all of the licenses are compliant.
+func (p *licenseParser) parseAndExpr() (licenseStatus, error) {
+	status, err := p.parseWithExpr()
+
+	for err == nil && p.peek() == "AND" {
+		p.pos++
+
+		var v licenseStatus
+		if v, err = p.parseWithExpr(); v > status {
+			status = v
+		}
+	}
+
+	return status, err
+}
+
+// parseWithExpr is decided by the license unless the exception is denied.
+func (p *licenseParser) parseWithExpr() (licenseStatus, error) {
+	status, err := p.parseAtom()
+	if err != nil || p.peek() != "WITH" {
+		return status, err
+	}
+
+	p.pos++
+
+	id, err := p.parseId()
+	if err != nil {
+		return status, err
+	}
+
+	if p.cfg.status(id) == licenseDenied {
+		p.denied = append(p.denied, id)
+
+		return licenseDenied, nil
+	}
+
+	return status, nil
+}
+
+func (p *licenseParser) parseAtom() (licenseStatus, error) {
+	if p.peek() != "(" {
+		id, err := p.parseId()
+		if err != nil {
+			return licenseUnknown, err
+		}
+
+		status := p.cfg.status(id)
+
+		switch status {
+		case licenseUnknown:
+			p.unknown = append(p.unknown, id)
+
+		case licenseDenied:
+			p.denied = append(p.denied, id)
+		}
+
+		return status, nil
+	}
+
+	p.pos++
+
+	status, err := p.parseExpr()
+	if err != nil {
+		return status, err
+	}
+
+	if p.peek() != ")" {
+		return status, errors.New("invalid license expression, missing )")
+	}
+
+	p.pos++
+
+	return status, nil
+}
+
+func (p *licenseParser) parseId() (string, error) {
+	switch v := p.peek(); v {
+	case "", "(", ")", "AND", "OR", "WITH":
+		return "", errors.New("invalid license expression, missing license id")
+
+	default:
+		p.pos++
+
+		return v, nil
+	}
+}
+
+// checkLicense checks the license of application against the config and
+// answers the check item about the license.
+func (entity *SoftwarePkgBasicInfo) checkLicense() {
+	entity.Review.LicenseCheck = nil
+
+	cfg := &config.License
+	if !cfg.enabled() || entity.Review.checkItem(cfg.CheckItem) == nil {
+		return
+	}
+
+	v := &LicenseCheck{Index: cfg.CheckItem}
+
+	if l := entity.Application.License; l == nil {
+		v.Reason = "missing license"
+	} else {
+		v.License = l.License()
+		v.Compliant, v.Reason = cfg.check(v.License)
+	}
+
+	entity.Review.LicenseCheck = v
+}
+
+// fillLicense sets the license declared in the spec if it is not given.
+func (app *SoftwarePkgApplication) fillLicense(spec *SoftwarePkgSpec) {
+	if app.License == nil && spec.License != "" {
+		app.License, _ = dp.NewLicense(spec.License)
+	}
+}
+
+func licenseString(v dp.License) string {
+	if v == nil {
+		return ""
+	}
+
+	return v.License()
+}
@@ -14,6 +14,17 @@ type SoftwarePkgOperationLog struct {
 	Time   int64
 	User   dp.Account
 	Action dp.PackageOperationLogAction
+
+	Details SoftwarePkgOperationLogDetails
+}
+
+// SoftwarePkgOperationLogDetails is the details of an operation.
+// The fields which are irrelevant to the operation are empty.
+type SoftwarePkgOperationLogDetails struct {
+	OldPhase dp.PackagePhase
+	NewPhase dp.PackagePhase
+	CIPRNum  int
+	Reason   string
 }
 
 func (log *SoftwarePkgOperationLog) String() string {
@@ -28,11 +39,13 @@ func (log *SoftwarePkgOperationLog) String() string {
 
 func NewSoftwarePkgOperationLog(
 	user dp.Account, action dp.PackageOperationLogAction, pkgId string,
+	details *SoftwarePkgOperationLogDetails,
 ) SoftwarePkgOperationLog {
 	return SoftwarePkgOperationLog{
-		PkgId:  pkgId,
-		Time:   utils.Now(),
-		User:   user,
-		Action: action,
+		PkgId:   pkgId,
+		Time:    utils.Now(),
+		User:    user,
+		Action:  action,
+		Details: *details,
 	}
 }
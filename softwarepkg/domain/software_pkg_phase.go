@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/opensourceways/software-package-server/common/allerror"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+	"github.com/opensourceways/software-package-server/utils"
+)
+
+// The actions which may be done on a pkg by the users.
+const (
+	PhaseActionUpdate  = "update"
+	PhaseActionComment = "comment"
+	PhaseActionReview  = "review"
+	PhaseActionReject  = "reject"
+	PhaseActionAbandon = "abandon"
+	PhaseActionRerunCI = "rerun_ci"
+	PhaseActionRetire  = "retire"
+	PhaseActionReopen  = "reopen"
+)
+
+// The actions which are done on a pkg by the system.
+const (
+	phaseActionHandleCI       = "handle_ci"
+	phaseActionInitialize     = "initialize"
+	phaseActionHandleExisted  = "handle_existed"
+	phaseActionCreateRepo     = "create_repo"
+	phaseActionHandleCodeSave = "handle_code_save"
+)
+
+var (
+	phaseReviewing        = dp.PackagePhaseReviewing.PackagePhase()
+	phaseChangesRequested = dp.PackagePhaseChangesRequested.PackagePhase()
+	phaseApproved         = dp.PackagePhaseApproved.PackagePhase()
+	phaseCreatingRepo     = dp.PackagePhaseCreatingRepo.PackagePhase()
+	phaseImported         = dp.PackagePhaseImported.PackagePhase()
+	phaseClosed           = dp.PackagePhaseClosed.PackagePhase()
+	phaseRetired          = dp.PackagePhaseRetired.PackagePhase()
+
+	userActions = []string{
+		PhaseActionUpdate,
+		PhaseActionComment,
+		PhaseActionReview,
+		PhaseActionReject,
+		PhaseActionAbandon,
+		PhaseActionRerunCI,
+		PhaseActionRetire,
+		PhaseActionReopen,
+	}
+
+	// phaseTransitions is the transition table of the phase of pkg.
+	// It maps an action to the phases it can be done in, and each of
+	// them maps to the phases which the pkg can be in after the action.
+	phaseTransitions = map[string]map[string][]string{
+		PhaseActionUpdate: {
+			phaseReviewing:        {phaseReviewing},
+			phaseChangesRequested: {phaseReviewing, phaseChangesRequested},
+		},
+		PhaseActionComment: {
+			phaseReviewing:        {phaseReviewing},
+			phaseChangesRequested: {phaseChangesRequested},
+		},
+		PhaseActionReview: {
+			phaseReviewing:        {phaseReviewing, phaseChangesRequested, phaseApproved},
+			phaseChangesRequested: {phaseReviewing, phaseChangesRequested, phaseApproved},
+		},
+		PhaseActionReject: {
+			phaseReviewing:        {phaseClosed},
+			phaseChangesRequested: {phaseClosed},
+		},
+		PhaseActionAbandon: {
+			phaseReviewing:        {phaseClosed},
+			phaseChangesRequested: {phaseClosed},
+		},
+		PhaseActionRerunCI: {
+			phaseReviewing:        {phaseReviewing},
+			phaseChangesRequested: {phaseChangesRequested},
+		},
+		PhaseActionRetire: {
+			phaseImported: {phaseRetired},
+		},
+		PhaseActionReopen: {
+			phaseClosed: {phaseReviewing},
+		},
+		phaseActionHandleCI: {
+			phaseReviewing:        {phaseReviewing},
+			phaseChangesRequested: {phaseChangesRequested},
+		},
+		// the pkgs in the creating_repo phase were approved before
+		// the approved phase was introduced.
+		phaseActionInitialize: {
+			phaseApproved:     {phaseCreatingRepo},
+			phaseCreatingRepo: {phaseCreatingRepo},
+		},
+		phaseActionCreateRepo: {
+			phaseApproved:     {phaseCreatingRepo},
+			phaseCreatingRepo: {phaseCreatingRepo},
+		},
+		phaseActionHandleCodeSave: {
+			phaseApproved:     {phaseImported},
+			phaseCreatingRepo: {phaseImported},
+		},
+		phaseActionHandleExisted: {
+			phaseApproved:     {phaseClosed},
+			phaseCreatingRepo: {phaseClosed},
+		},
+	}
+)
+
+// errorIncorrectPhase
+type errorIncorrectPhase struct {
+	action string
+	from   string
+	to     string
+}
+
+func (e errorIncorrectPhase) Error() string {
+	if e.to == "" {
+		return fmt.Sprintf("can't %s the pkg in the phase of %s", e.action, e.from)
+	}
+
+	return fmt.Sprintf(
+		"can't %s the pkg from the phase of %s to %s", e.action, e.from, e.to,
+	)
+}
+
+func (e errorIncorrectPhase) ErrorCode() string {
+	return allerror.ErrorCodeIncorrectPhase
+}
+
+func canDoInPhase(phase dp.PackagePhase, action string) bool {
+	_, ok := phaseTransitions[action][phase.PackagePhase()]
+
+	return ok
+}
+
+// AllowedActions returns the actions that the users can do on the pkg in current phase.
+// It doesn't check whether a user has the permission to do it.
+func (entity *SoftwarePkgBasicInfo) AllowedActions() []string {
+	r := make([]string, 0, len(userActions))
+
+	for _, action := range userActions {
+		if canDoInPhase(entity.Phase, action) {
+			r = append(r, action)
+		}
+	}
+
+	return r
+}
+
+// checkPhase checks whether the action can be done in current phase.
+func (entity *SoftwarePkgBasicInfo) checkPhase(action string) error {
+	if !canDoInPhase(entity.Phase, action) {
+		return errorIncorrectPhase{
+			action: action,
+			from:   entity.Phase.PackagePhase(),
+		}
+	}
+
+	return nil
+}
+
+// transit moves the pkg to the phase by the action.
+func (entity *SoftwarePkgBasicInfo) transit(action string, phase dp.PackagePhase) error {
+	from := entity.Phase.PackagePhase()
+
+	for _, to := range phaseTransitions[action][from] {
+		if to == phase.PackagePhase() {
+			entity.Phase = phase
+
+			if phase.IsClosed() {
+				entity.ClosedAt = utils.Now()
+			}
+
+			return nil
+		}
+	}
+
+	return errorIncorrectPhase{
+		action: action,
+		from:   from,
+		to:     phase.PackagePhase(),
+	}
+}
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+func TestPhaseTransition(t *testing.T) {
+	phases := []dp.PackagePhase{
+		dp.PackagePhaseReviewing,
+		dp.PackagePhaseChangesRequested,
+		dp.PackagePhaseApproved,
+		dp.PackagePhaseCreatingRepo,
+		dp.PackagePhaseImported,
+		dp.PackagePhaseClosed,
+		dp.PackagePhaseRetired,
+	}
+
+	actions := append([]string{
+		phaseActionHandleCI,
+		phaseActionInitialize,
+		phaseActionHandleExisted,
+		phaseActionCreateRepo,
+		phaseActionHandleCodeSave,
+	}, userActions...)
+
+	// allowed lists every transition in the form of "action: from -> to".
+	allowed := map[string]bool{
+		"update: reviewing -> reviewing":                    true,
+		"update: changes_requested -> reviewing":            true,
+		"update: changes_requested -> changes_requested":    true,
+		"comment: reviewing -> reviewing":                   true,
+		"comment: changes_requested -> changes_requested":   true,
+		"review: reviewing -> reviewing":                    true,
+		"review: reviewing -> changes_requested":            true,
+		"review: reviewing -> approved":                     true,
+		"review: changes_requested -> reviewing":            true,
+		"review: changes_requested -> changes_requested":    true,
+		"review: changes_requested -> approved":             true,
+		"reject: reviewing -> closed":                       true,
+		"reject: changes_requested -> closed":               true,
+		"abandon: reviewing -> closed":                      true,
+		"abandon: changes_requested -> closed":              true,
+		"rerun_ci: reviewing -> reviewing":                  true,
+		"rerun_ci: changes_requested -> changes_requested":  true,
+		"retire: imported -> retired":                       true,
+		"reopen: closed -> reviewing":                       true,
+		"handle_ci: reviewing -> reviewing":                 true,
+		"handle_ci: changes_requested -> changes_requested": true,
+		"initialize: approved -> creating_repo":             true,
+		"initialize: creating_repo -> creating_repo":        true,
+		"create_repo: approved -> creating_repo":            true,
+		"create_repo: creating_repo -> creating_repo":       true,
+		"handle_code_save: approved -> imported":            true,
+		"handle_code_save: creating_repo -> imported":       true,
+		"handle_existed: approved -> closed":                true,
+		"handle_existed: creating_repo -> closed":           true,
+	}
+
+	for _, action := range actions {
+		for _, from := range phases {
+			canDo := false
+
+			for _, to := range phases {
+				name := action + ": " + from.PackagePhase() + " -> " + to.PackagePhase()
+				want := allowed[name]
+				canDo = canDo || want
+
+				pkg := SoftwarePkgBasicInfo{Phase: from}
+				err := pkg.transit(action, to)
+
+				if (err == nil) != want {
+					t.Errorf("%s: got err %v, want allowed %v", name, err, want)
+
+					continue
+				}
+
+				if err != nil {
+					if pkg.Phase != from {
+						t.Errorf("%s: the phase is changed on failure", name)
+					}
+
+					continue
+				}
+
+				if pkg.Phase != to {
+					t.Errorf("%s: got phase %s", name, pkg.Phase.PackagePhase())
+				}
+
+				if to.IsClosed() != (pkg.ClosedAt != 0) {
+					t.Errorf("%s: got closed at %d", name, pkg.ClosedAt)
+				}
+			}
+
+			pkg := SoftwarePkgBasicInfo{Phase: from}
+			if err := pkg.checkPhase(action); (err == nil) != canDo {
+				t.Errorf(
+					"%s in %s: got err %v, want allowed %v",
+					action, from.PackagePhase(), err, canDo,
+				)
+			}
+		}
+	}
+}
@@ -1,18 +1,86 @@
 package domain
 
 import (
+	"fmt"
 	"sort"
 
+	"github.com/opensourceways/software-package-server/common/allerror"
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
 )
 
+// CheckItems returns the check items that a pkg of the sig and platform
+// should be reviewed against.
+func CheckItems(sig dp.ImportingPkgSig, platform dp.PackagePlatform) []CheckItem {
+	return config.checkItems(sig, platform)
+}
+
 // CheckItemReviewInfos
 type CheckItemReviewInfos struct {
 	Item  *CheckItem
 	Infos []CheckItemReviewInfo
+
+	// LicenseCheck answers the item if it is about the license.
+	LicenseCheck *LicenseCheck
 }
 
 func (r *CheckItemReviewInfos) Result() dp.CheckItemResult {
+	if r.LicenseCheck != nil {
+		return r.licenseResult()
+	}
+
+	return r.ownersResult()
+}
+
+// licenseResult is decided by the latest review of tc if any tc member
+// reviewed the item, so that the tc can override the result of checking the
+// license. Otherwise, the compliant license passes the item unless the owners
+// don't pass it, and the non-compliant one is advisory until the owners
+// review the item.
+func (r *CheckItemReviewInfos) licenseResult() dp.CheckItemResult {
+	if v := r.latestTCReview(); v != nil {
+		if v.Pass {
+			return dp.CheckItemPass
+		}
+
+		return dp.CheckItemNotPass
+	}
+
+	v := r.ownersResult()
+
+	if dp.IsCheckItemNotPass(v) {
+		return v
+	}
+
+	if r.LicenseCheck.Compliant {
+		return dp.CheckItemPass
+	}
+
+	if dp.IsCheckItemPass(v) {
+		return dp.CheckItemNotPass
+	}
+
+	return dp.CheckItemNoIdea
+}
+
+// latestTCReview returns the latest review of tc which is not stale.
+func (r *CheckItemReviewInfos) latestTCReview() *CheckItemReviewInfo {
+	var latest *CheckItemReviewInfo
+
+	for i := range r.Infos {
+		v := &r.Infos[i]
+		if v.Stale || !v.isTC() {
+			continue
+		}
+
+		if latest == nil || v.ReviewedAt > latest.ReviewedAt {
+			latest = v
+		}
+	}
+
+	return latest
+}
+
+func (r *CheckItemReviewInfos) ownersResult() dp.CheckItemResult {
 	if len(r.Infos) == 0 {
 		return dp.CheckItemNoIdea
 	}
@@ -20,7 +88,7 @@ func (r *CheckItemReviewInfos) Result() dp.CheckItemResult {
 	pass := false
 
 	for i := range r.Infos {
-		if v := &r.Infos[i]; r.Item.isOwner(v.Role) {
+		if v := &r.Infos[i]; !v.Stale && r.Item.isOwner(v.Role) {
 			if !v.Pass {
 				return dp.CheckItemNotPass
 			}
@@ -40,9 +108,60 @@ func (r *CheckItemReviewInfos) Result() dp.CheckItemResult {
 type SoftwarePkgReview struct {
 	Items   []CheckItem
 	Reviews []UserReview
+
+	// LicenseCheck is nil if the license is not checked automatically.
+	LicenseCheck *LicenseCheck
 }
 
+func (r *SoftwarePkgReview) isLicenseItem(item *CheckItem) bool {
+	return r.LicenseCheck != nil && r.LicenseCheck.Index == item.Index
+}
+
+func (r *SoftwarePkgReview) checkItem(index int) *CheckItem {
+	for i := range r.Items {
+		if r.Items[i].Index == index {
+			return &r.Items[i]
+		}
+	}
+
+	return nil
+}
+
+func (r *SoftwarePkgReview) validate(ur *UserReview) error {
+	for i := range ur.Items {
+		if index := ur.Items[i].Index; r.checkItem(index) == nil {
+			return allerror.New(
+				allerror.ErrorCodeUnknownCheckItem,
+				fmt.Sprintf("unknown check item: %d", index),
+			)
+		}
+	}
+
+	return nil
+}
+
+// add merges the review into the previous one of the same reviewer.
+// The items reviewed again will override the old ones, and ur will be
+// updated to the merged review.
 func (r *SoftwarePkgReview) add(ur *UserReview) {
+	for i := range r.Reviews {
+		if old := &r.Reviews[i]; dp.IsSameAccount(old.User, ur.User) {
+			for j := range old.Items {
+				if !ur.hasReviewed(old.Items[j].Index) {
+					ur.Items = append(ur.Items, old.Items[j])
+				}
+			}
+
+			sort.Slice(ur.Items, func(m, n int) bool {
+				return ur.Items[m].Index < ur.Items[n].Index
+			})
+
+			*old = *ur
+
+			return
+		}
+	}
+
 	r.Reviews = append(r.Reviews, *ur)
 }
 
@@ -53,12 +172,71 @@ func (r *SoftwarePkgReview) pass() bool {
 		}
 	}
 
-	return true
+	q := r.Quorum()
+
+	return q.IsReached()
+}
+
+// changesRequested returns true if any check item is not passed by its owners.
+func (r *SoftwarePkgReview) changesRequested() bool {
+	for i := range r.Items {
+		if rf := r.CheckItemReview(&r.Items[i]); dp.IsCheckItemNotPass(rf.Result()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// markStale marks the reviews of the check items which are affected as stale.
+func (r *SoftwarePkgReview) markStale(affected func(*CheckItem) bool) {
+	for i := range r.Reviews {
+		items := r.Reviews[i].Items
+
+		for j := range items {
+			if item := r.checkItem(items[j].Index); item == nil || affected(item) {
+				items[j].Stale = true
+			}
+		}
+	}
+}
+
+func (r *SoftwarePkgReview) Quorum() ReviewQuorum {
+	q := ReviewQuorum{
+		MinNumApprovedByTC:            config.minNumApprovedByTC(),
+		MinNumApprovedBySigMaintainer: config.minNumApprovedBySigMaintainer(),
+	}
+
+	for i := range r.Reviews {
+		ur := &r.Reviews[i]
+
+		if ur.isTC() && ur.hasNotPass() {
+			q.VetoedByTC = append(q.VetoedByTC, ur.User)
+		}
+
+		if !ur.isApproved(r.Items) {
+			continue
+		}
+
+		if ur.isTC() {
+			q.ApprovedByTC = append(q.ApprovedByTC, ur.User)
+		}
+
+		if ur.isSigMaintainer() {
+			q.ApprovedBySigMaintainer = append(q.ApprovedBySigMaintainer, ur.User)
+		}
+	}
+
+	return q
 }
 
 func (r *SoftwarePkgReview) CheckItemReview(item *CheckItem) (rf CheckItemReviewInfos) {
 	rf.Item = item
 
+	if r.isLicenseItem(item) {
+		rf.LicenseCheck = r.LicenseCheck
+	}
+
 	if len(r.Reviews) == 0 {
 		return
 	}
@@ -91,14 +269,54 @@ type CheckItemReviewInfo struct {
 	*CheckItemReview
 }
 
+// ReviewQuorum
+type ReviewQuorum struct {
+	MinNumApprovedByTC            int
+	MinNumApprovedBySigMaintainer int
+
+	ApprovedByTC            []dp.Account
+	ApprovedBySigMaintainer []dp.Account
+
+	// VetoedByTC are the tc members who didn't pass some check items.
+	VetoedByTC []dp.Account
+}
+
+func (q *ReviewQuorum) IsVetoed() bool {
+	return len(q.VetoedByTC) > 0
+}
+
+func (q *ReviewQuorum) IsReached() bool {
+	return !q.IsVetoed() &&
+		len(q.ApprovedByTC) >= q.MinNumApprovedByTC &&
+		len(q.ApprovedBySigMaintainer) >= q.MinNumApprovedBySigMaintainer
+}
+
 // Reviewer
 type Reviewer struct {
 	User dp.Account
-	Role []string
+	Role []dp.CommunityRole
+}
+
+func (r *Reviewer) hasRole(role dp.CommunityRole) bool {
+	return hasCommunityRole(r.Role, role)
 }
 
 func (r *Reviewer) isTC() bool {
-	return false // TODO
+	return r.hasRole(dp.CommunityRoleTC)
+}
+
+func (r *Reviewer) isSigMaintainer() bool {
+	return r.hasRole(dp.CommunityRoleSigMaintainer)
+}
+
+func hasCommunityRole(roles []dp.CommunityRole, role dp.CommunityRole) bool {
+	for _, v := range roles {
+		if dp.IsSameCommunityRole(v, role) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // UserReview
@@ -123,11 +341,62 @@ func (r *UserReview) CheckItemReview(item *CheckItem) (info CheckItemReviewInfo,
 	return
 }
 
+// isApproved returns true if the reviewer passed all the check items
+// that the reviewer owns.
+func (r *UserReview) isApproved(items []CheckItem) bool {
+	owned := false
+
+	for i := range items {
+		item := &items[i]
+		if !item.isOwner(r.Role) {
+			continue
+		}
+
+		owned = true
+
+		if v, exist := r.CheckItemReview(item); !exist || v.Stale || !v.Pass {
+			return false
+		}
+	}
+
+	return owned
+}
+
+func (r *UserReview) hasReviewed(index int) bool {
+	for i := range r.Items {
+		if r.Items[i].Index == index {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *UserReview) hasNotPass() bool {
+	for i := range r.Items {
+		if v := &r.Items[i]; !v.Stale && !v.Pass {
+			return true
+		}
+	}
+
+	return false
+}
+
 // CheckItemReview
 type CheckItemReview struct {
 	Index int
 	Pass  bool
 	Desc  string
+
+	// Revision is the revision of application which the review was made against.
+	Revision int
+
+	// ReviewedAt is the time when the item was reviewed.
+	ReviewedAt int64
+
+	// Stale means the pkg was changed after the review,
+	// and the review doesn't count any more.
+	Stale bool
 }
 
 // CheckItem
@@ -135,15 +404,33 @@ type CheckItem struct {
 	Index  int
 	Item   string
 	Desc   string
-	Owners []string // TC, Sig maintainer, Committer
+	Owners []dp.CommunityRole
+
+	// Fields are the fields of application which the item is about.
+	// It is about the source code if empty.
+	Fields []string
 }
 
-func (item *CheckItem) isOwner(Role []string) bool {
-	for _, role := range Role {
-		for _, owner := range item.Owners {
-			if role == owner {
-				return true
-			}
+// isAffectedBy returns true if any of the fields which the item is about is changed.
+func (item *CheckItem) isAffectedBy(changed []string) bool {
+	fields := item.Fields
+	if len(fields) == 0 {
+		fields = sourceCodeFields
+	}
+
+	for _, f := range changed {
+		if containsField(fields, f) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (item *CheckItem) isOwner(roles []dp.CommunityRole) bool {
+	for _, role := range roles {
+		if hasCommunityRole(item.Owners, role) {
+			return true
 		}
 	}
 
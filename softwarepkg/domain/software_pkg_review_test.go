@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+func TestLicenseResult(t *testing.T) {
+	item := CheckItem{Owners: []dp.CommunityRole{dp.CommunityRoleSigMaintainer}}
+
+	tc := &Reviewer{Role: []dp.CommunityRole{dp.CommunityRoleTC}}
+	owner := &Reviewer{Role: []dp.CommunityRole{dp.CommunityRoleSigMaintainer}}
+	other := &Reviewer{Role: []dp.CommunityRole{dp.CommunityRoleCommitter}}
+
+	info := func(r *Reviewer, pass bool, at int64) CheckItemReviewInfo {
+		return CheckItemReviewInfo{
+			Reviewer:        r,
+			CheckItemReview: &CheckItemReview{Pass: pass, ReviewedAt: at},
+		}
+	}
+
+	stale := info(tc, true, 9)
+	stale.Stale = true
+
+	cases := []struct {
+		name      string
+		compliant bool
+		infos     []CheckItemReviewInfo
+		want      dp.CheckItemResult
+	}{
+		{"compliant without review", true, nil, dp.CheckItemPass},
+		{"non-compliant without review is advisory", false, nil, dp.CheckItemNoIdea},
+		{
+			"non-compliant reviewed by others is advisory", false,
+			[]CheckItemReviewInfo{info(other, true, 1)}, dp.CheckItemNoIdea,
+		},
+		{
+			"non-compliant passed by owner", false,
+			[]CheckItemReviewInfo{info(owner, true, 1)}, dp.CheckItemNotPass,
+		},
+		{
+			"compliant not passed by owner", true,
+			[]CheckItemReviewInfo{info(owner, false, 1)}, dp.CheckItemNotPass,
+		},
+		{
+			"non-compliant overridden by tc", false,
+			[]CheckItemReviewInfo{info(owner, false, 1), info(tc, true, 2)}, dp.CheckItemPass,
+		},
+		{
+			"latest tc review wins", true,
+			[]CheckItemReviewInfo{info(tc, false, 3), info(tc, true, 2)}, dp.CheckItemNotPass,
+		},
+		{
+			"stale tc review is ignored", false,
+			[]CheckItemReviewInfo{stale}, dp.CheckItemNoIdea,
+		},
+	}
+
+	for _, c := range cases {
+		r := CheckItemReviewInfos{
+			Item:         &item,
+			Infos:        c.infos,
+			LicenseCheck: &LicenseCheck{Compliant: c.compliant},
+		}
+
+		if got := r.Result(); got.CheckItemResult() != c.want.CheckItemResult() {
+			t.Errorf("%s: got %s, want %s", c.name, got.CheckItemResult(), c.want.CheckItemResult())
+		}
+	}
+}
+
+func TestQuorum(t *testing.T) {
+	one, two := 1, 2
+
+	old := config
+	config = Config{MinNumApprovedByTC: &one, MinNumApprovedBySigMaintainer: &two}
+	defer func() { config = old }()
+
+	items := []CheckItem{
+		{Index: 1, Owners: []dp.CommunityRole{dp.CommunityRoleTC}},
+		{Index: 2, Owners: []dp.CommunityRole{dp.CommunityRoleSigMaintainer}},
+	}
+
+	review := func(role dp.CommunityRole, index int, pass, stale bool) UserReview {
+		return UserReview{
+			Reviewer: Reviewer{Role: []dp.CommunityRole{role}},
+			Items:    []CheckItemReview{{Index: index, Pass: pass, Stale: stale}},
+		}
+	}
+
+	tc := func(pass, stale bool) UserReview {
+		return review(dp.CommunityRoleTC, 1, pass, stale)
+	}
+
+	maintainer := func(pass, stale bool) UserReview {
+		return review(dp.CommunityRoleSigMaintainer, 2, pass, stale)
+	}
+
+	cases := []struct {
+		name    string
+		reviews []UserReview
+		tc      int
+		sig     int
+		vetoed  bool
+		reached bool
+	}{
+		{"no review", nil, 0, 0, false, false},
+		{
+			"approved by enough reviewers",
+			[]UserReview{tc(true, false), maintainer(true, false), maintainer(true, false)},
+			1, 2, false, true,
+		},
+		{
+			"too few sig maintainers",
+			[]UserReview{tc(true, false), maintainer(true, false)},
+			1, 1, false, false,
+		},
+		{
+			"stale approval does not count",
+			[]UserReview{tc(true, false), maintainer(true, false), maintainer(true, true)},
+			1, 1, false, false,
+		},
+		{
+			"vetoed by tc",
+			[]UserReview{
+				tc(true, false), tc(false, false),
+				maintainer(true, false), maintainer(true, false),
+			},
+			1, 2, true, false,
+		},
+		{
+			"stale veto does not count",
+			[]UserReview{
+				tc(true, false), tc(false, true),
+				maintainer(true, false), maintainer(true, false),
+			},
+			1, 2, false, true,
+		},
+		{
+			"not passed by sig maintainer is no veto",
+			[]UserReview{
+				tc(true, false), maintainer(false, false),
+				maintainer(true, false), maintainer(true, false),
+			},
+			1, 2, false, true,
+		},
+		{
+			"reviewer without owned item does not count",
+			[]UserReview{
+				tc(true, false), review(dp.CommunityRoleCommitter, 2, true, false),
+				maintainer(true, false),
+			},
+			1, 1, false, false,
+		},
+	}
+
+	for _, c := range cases {
+		r := SoftwarePkgReview{Items: items, Reviews: c.reviews}
+
+		q := r.Quorum()
+
+		if len(q.ApprovedByTC) != c.tc || len(q.ApprovedBySigMaintainer) != c.sig {
+			t.Errorf(
+				"%s: got %d/%d approvals, want %d/%d", c.name,
+				len(q.ApprovedByTC), len(q.ApprovedBySigMaintainer), c.tc, c.sig,
+			)
+		}
+
+		if q.IsVetoed() != c.vetoed {
+			t.Errorf("%s: got vetoed %v, want %v", c.name, q.IsVetoed(), c.vetoed)
+		}
+
+		if q.IsReached() != c.reached {
+			t.Errorf("%s: got reached %v, want %v", c.name, q.IsReached(), c.reached)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package domain
+
+import (
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+	"github.com/opensourceways/software-package-server/utils"
+)
+
+// The fields of application which are compared between two revisions.
+const (
+	ApplicationFieldSpecURL   = "spec_url"
+	ApplicationFieldSrcRPMURL = "src_rpm_url"
+	ApplicationFieldUpstream  = "upstream"
+	ApplicationFieldDesc      = "desc"
+	ApplicationFieldPlatform  = "platform"
+	ApplicationFieldSig       = "sig"
+	ApplicationFieldReason    = "reason"
+	ApplicationFieldLicense   = "license"
+)
+
+var (
+	applicationFields = []string{
+		ApplicationFieldSpecURL,
+		ApplicationFieldSrcRPMURL,
+		ApplicationFieldUpstream,
+		ApplicationFieldDesc,
+		ApplicationFieldPlatform,
+		ApplicationFieldSig,
+		ApplicationFieldReason,
+		ApplicationFieldLicense,
+	}
+
+	// sourceCodeFields are the fields which decide the artifacts tested by the ci.
+	sourceCodeFields = applicationFields[:3]
+)
+
+func containsField(fields []string, v string) bool {
+	for _, f := range fields {
+		if f == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SoftwarePkgRevision is a numbered snapshot of the application of pkg.
+// A new one is created each time the application is updated.
+type SoftwarePkgRevision struct {
+	Number      int
+	Author      dp.Account
+	CreatedAt   int64
+	Application SoftwarePkgApplication
+}
+
+func newSoftwarePkgRevision(
+	number int, author dp.Account, app *SoftwarePkgApplication,
+) SoftwarePkgRevision {
+	return SoftwarePkgRevision{
+		Number:      number,
+		Author:      author,
+		CreatedAt:   utils.Now(),
+		Application: *app,
+	}
+}
+
+// ApplicationFieldDiff is the change of a field of application.
+type ApplicationFieldDiff struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// DiffRevisions returns the fields of application which are changed from one revision to another.
+func DiffRevisions(from, to *SoftwarePkgRevision) []ApplicationFieldDiff {
+	return diffApplication(&from.Application, &to.Application)
+}
+
+func diffApplication(from, to *SoftwarePkgApplication) []ApplicationFieldDiff {
+	of := from.fields()
+	nf := to.fields()
+
+	var r []ApplicationFieldDiff
+
+	for i := range of {
+		if of[i].value != nf[i].value {
+			r = append(r, ApplicationFieldDiff{
+				Field: of[i].name,
+				Old:   of[i].value,
+				New:   nf[i].value,
+			})
+		}
+	}
+
+	return r
+}
+
+// changedFields returns the names of the fields which are changed from one application to another.
+func changedFields(from, to *SoftwarePkgApplication) []string {
+	diff := diffApplication(from, to)
+
+	r := make([]string, len(diff))
+	for i := range diff {
+		r[i] = diff[i].Field
+	}
+
+	return r
+}
+
+func isSourceCodeChanged(changed []string) bool {
+	for _, f := range sourceCodeFields {
+		if containsField(changed, f) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type applicationField struct {
+	name  string
+	value string
+}
+
+func (app *SoftwarePkgApplication) fields() []applicationField {
+	return []applicationField{
+		{ApplicationFieldSpecURL, app.SourceCode.SpecURL.URL()},
+		{ApplicationFieldSrcRPMURL, app.SourceCode.SrcRPMURL.URL()},
+		{ApplicationFieldUpstream, app.SourceCode.Upstream.URL()},
+		{ApplicationFieldDesc, app.PackageDesc.PackageDesc()},
+		{ApplicationFieldPlatform, app.PackagePlatform.PackagePlatform()},
+		{ApplicationFieldSig, app.ImportingPkgSig.ImportingPkgSig()},
+		{ApplicationFieldReason, app.ReasonToImportPkg.ReasonToImportPkg()},
+		{ApplicationFieldLicense, licenseString(app.License)},
+	}
+}
+
+// addRevision snapshots the current application as a new revision.
+func (entity *SoftwarePkgBasicInfo) addRevision(author dp.Account) {
+	entity.Revision++
+
+	entity.NewRevisions = append(
+		entity.NewRevisions,
+		newSoftwarePkgRevision(entity.Revision, author, &entity.Application),
+	)
+}
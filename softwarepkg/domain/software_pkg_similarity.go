@@ -0,0 +1,229 @@
+package domain
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+	"github.com/opensourceways/software-package-server/utils"
+)
+
+// The reasons why a pkg is similar to the one to be applied.
+const (
+	// SimilarReasonSameName means the names are same after normalized,
+	// such as PyYAML and python-pyyaml.
+	SimilarReasonSameName = "same_name"
+
+	// SimilarReasonNearName means the names are close in edit distance.
+	SimilarReasonNearName = "near_name"
+
+	// SimilarReasonSameUpstream means the upstreams are the same repo.
+	SimilarReasonSameUpstream = "same_upstream"
+)
+
+// SimilarityConfig
+type SimilarityConfig struct {
+	// MaxEditDistance is the max edit distance between the normalized names
+	// of the near pkgs. It is also limited to a third of the shorter name,
+	// so that the short names will not be near to each other easily.
+	MaxEditDistance int `json:"max_edit_distance"`
+
+	// MaxNumOfSimilarPkgs is the max number of similar pkgs returned.
+	MaxNumOfSimilarPkgs int `json:"max_num_of_similar_pkgs"`
+}
+
+func (cfg *SimilarityConfig) setDefault() {
+	if cfg.MaxEditDistance <= 0 {
+		cfg.MaxEditDistance = 2
+	}
+
+	if cfg.MaxNumOfSimilarPkgs <= 0 {
+		cfg.MaxNumOfSimilarPkgs = 10
+	}
+}
+
+// SimilarSoftwarePkg is an imported or in-flight pkg which may be the same
+// software as the one to be applied.
+type SimilarSoftwarePkg struct {
+	Id       string
+	PkgName  dp.PackageName
+	Phase    dp.PackagePhase
+	Upstream dp.URL
+	Reason   string
+	Distance int
+}
+
+// SimilarityKey is the normalized name and upstream of a pkg, by which the
+// candidates of the similar pkgs can be found without comparing all the pkgs.
+type SimilarityKey struct {
+	Name     string
+	Upstream string
+}
+
+func NewSimilarityKey(name dp.PackageName, upstream dp.URL) SimilarityKey {
+	return SimilarityKey{
+		Name:     normalizePkgName(name.PackageName()),
+		Upstream: normalizeUpstream(upstream),
+	}
+}
+
+// NameLen returns the number of characters of the name, which is
+// the same unit as the edit distance.
+func (k SimilarityKey) NameLen() int {
+	return utils.StrLen(k.Name)
+}
+
+// MaxNameDistance returns the max edit distance of the names near to it.
+// The lengths of those names differ from it at most by the distance.
+func (k SimilarityKey) MaxNameDistance() int {
+	return minInt(config.Similarity.MaxEditDistance, k.NameLen()/3)
+}
+
+// SimilarSoftwarePkgs returns the pkgs which are similar to the name or the upstream.
+// The pkgs of the same name are excluded, because they are checked by the existence.
+// The more similar, the former.
+func SimilarSoftwarePkgs(
+	name dp.PackageName, upstream dp.URL, pkgs []SoftwarePkgBasicInfo,
+) []SimilarSoftwarePkg {
+	cfg := &config.Similarity
+	key := NewSimilarityKey(name, upstream)
+	target, targetUpstream := key.Name, key.Upstream
+
+	var r []SimilarSoftwarePkg
+
+	for i := range pkgs {
+		pkg := &pkgs[i]
+
+		if pkg.PkgName.PackageName() == name.PackageName() {
+			continue
+		}
+
+		v := SimilarSoftwarePkg{
+			Id:       pkg.Id,
+			PkgName:  pkg.PkgName,
+			Phase:    pkg.Phase,
+			Upstream: pkg.Application.SourceCode.Upstream,
+		}
+
+		s := normalizePkgName(pkg.PkgName.PackageName())
+
+		switch {
+		case s == target:
+			v.Reason = SimilarReasonSameName
+
+		case targetUpstream != "" && targetUpstream == normalizeUpstream(v.Upstream):
+			v.Reason = SimilarReasonSameUpstream
+			v.Distance = editDistance(s, target)
+
+		default:
+			d := editDistance(s, target)
+			if d > cfg.MaxEditDistance || d > minInt(utils.StrLen(s), key.NameLen())/3 {
+				continue
+			}
+
+			v.Reason = SimilarReasonNearName
+			v.Distance = d
+		}
+
+		r = append(r, v)
+	}
+
+	sortSimilarSoftwarePkgs(r)
+
+	if len(r) > cfg.MaxNumOfSimilarPkgs {
+		r = r[:cfg.MaxNumOfSimilarPkgs]
+	}
+
+	return r
+}
+
+// sortSimilarSoftwarePkgs sorts the pkgs by the reason and the distance.
+// The sort is stable, so the pkgs applied earlier are former.
+func sortSimilarSoftwarePkgs(v []SimilarSoftwarePkg) {
+	rank := func(item *SimilarSoftwarePkg) int {
+		switch item.Reason {
+		case SimilarReasonSameName:
+			return 0
+		case SimilarReasonSameUpstream:
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	sort.SliceStable(v, func(i, j int) bool {
+		if ri, rj := rank(&v[i]), rank(&v[j]); ri != rj {
+			return ri < rj
+		}
+
+		return v[i].Distance < v[j].Distance
+	})
+}
+
+// normalizePkgName lowercases the name, removes the prefix of language
+// ecosystem and the separators, so "PyYAML" and "python-pyyaml" are same.
+func normalizePkgName(name string) string {
+	name = dp.TrimEcosystemPrefix(strings.ToLower(name))
+
+	return strings.NewReplacer("-", "", "_", "", ".", "").Replace(name)
+}
+
+// normalizeUpstream returns the host and path of the upstream, such as
+// github.com/yaml/pyyaml. It is empty if the upstream is just a site.
+func normalizeUpstream(upstream dp.URL) string {
+	if upstream == nil {
+		return ""
+	}
+
+	u, err := url.Parse(upstream.URL())
+	if err != nil {
+		return ""
+	}
+
+	p := strings.TrimSuffix(strings.Trim(u.EscapedPath(), "/"), ".git")
+	if p == "" {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+
+	return host + "/" + strings.ToLower(p)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(minInt(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestSimilarityKeyNameLen(t *testing.T) {
+	old := config
+	config.Similarity = SimilarityConfig{MaxEditDistance: 2}
+	defer func() { config = old }()
+
+	cases := []struct {
+		name     string
+		len      int
+		distance int
+	}{
+		{"pyyaml", 6, 2},
+		{"yaml", 4, 1},
+		// 6 bytes but 3 characters
+		{"ééé", 3, 1},
+		// 18 bytes but 6 characters
+		{"中文名字测试", 6, 2},
+		{"", 0, 0},
+	}
+
+	for _, c := range cases {
+		k := SimilarityKey{Name: c.name}
+
+		if v := k.NameLen(); v != c.len {
+			t.Errorf("%s: got length %d, want %d", c.name, v, c.len)
+		}
+
+		if v := k.MaxNameDistance(); v != c.distance {
+			t.Errorf("%s: got max distance %d, want %d", c.name, v, c.distance)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"pyyaml", "pyyaml", 0},
+		{"pyyaml", "pyaml", 1},
+		{"yaml", "toml", 2},
+		{"ééé", "eee", 3},
+		{"中文", "中", 1},
+		{"", "abc", 3},
+	}
+
+	for _, c := range cases {
+		if v := editDistance(c.a, c.b); v != c.want {
+			t.Errorf("%s vs %s: got %d, want %d", c.a, c.b, v, c.want)
+		}
+	}
+}
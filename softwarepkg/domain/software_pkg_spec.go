@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/opensourceways/software-package-server/common/allerror"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+// SoftwarePkgSpec is the metadata parsed from the spec file of pkg.
+type SoftwarePkgSpec struct {
+	Name          string
+	Version       string
+	License       string
+	BuildRequires []string
+	Requires      []string
+	Sources       []string
+	Patches       []string
+	Files         []string
+
+	// SubPackages are the names of the packages declared by %package.
+	SubPackages []string
+
+	// ExistingDeps are the names of the dependencies which exist in the
+	// distribution. They are resolved when the spec is stored rather than
+	// parsed from the spec file.
+	ExistingDeps []string
+}
+
+// CheckName checks whether the name declared in the spec is the name of pkg.
+func (spec *SoftwarePkgSpec) CheckName(name dp.PackageName) error {
+	if v := name.PackageName(); spec.Name != v {
+		return allerror.New(
+			allerror.ErrorCodeSpecNameMismatch,
+			fmt.Sprintf("the name:%s of spec doesn't match the pkg name:%s", spec.Name, v),
+		)
+	}
+
+	return nil
+}
+
+// setSpec sets the spec parsed from the spec file of the application.
+// The name declared in the spec must be the name of pkg.
+func (entity *SoftwarePkgBasicInfo) setSpec(spec *SoftwarePkgSpec) error {
+	if err := spec.CheckName(entity.PkgName); err != nil {
+		return err
+	}
+
+	entity.Spec = *spec
+
+	return nil
+}
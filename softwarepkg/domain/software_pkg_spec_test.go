@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/opensourceways/software-package-server/common/allerror"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+func TestSetSpec(t *testing.T) {
+	dp.Init(&dp.Config{MaxLengthOfPackageName: 64}, nil)
+
+	name, err := dp.NewPackageName("python-foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		specName string
+		code     string
+	}{
+		{"same name", "python-foo", ""},
+		{"different name", "python3-foo", allerror.ErrorCodeSpecNameMismatch},
+		{"different case", "Python-foo", allerror.ErrorCodeSpecNameMismatch},
+		{"empty name", "", allerror.ErrorCodeSpecNameMismatch},
+	}
+
+	for _, c := range cases {
+		entity := SoftwarePkgBasicInfo{PkgName: name}
+		spec := SoftwarePkgSpec{Name: c.specName, Version: "1.0"}
+
+		err := entity.setSpec(&spec)
+
+		if c.code == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error:%s", c.name, err.Error())
+			} else if entity.Spec.Version != "1.0" {
+				t.Errorf("%s: the spec is not set", c.name)
+			}
+
+			continue
+		}
+
+		if code := ParseErrorCode(err); code != c.code {
+			t.Errorf("%s: got code %q, want %q", c.name, code, c.code)
+		}
+
+		if entity.Spec.Name != "" {
+			t.Errorf("%s: the spec should not be set", c.name)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+// maxFilesInNote is the max number of files listed in a review note,
+// so that the note will not be too long.
+const maxFilesInNote = 5
+
+var licenseFilePrefixes = []string{"LICENSE", "LICENCE", "COPYING", "COPYRIGHT", "NOTICE"}
+
+// SrcRPMFile is a file contained in the src.rpm. The files in the source
+// archives are contained too, and their paths are prefixed with the archive.
+type SrcRPMFile struct {
+	Path     string
+	Size     int64
+	IsBinary bool
+}
+
+func (f *SrcRPMFile) isLicense() bool {
+	name := strings.ToUpper(path.Base(f.Path))
+
+	for _, prefix := range licenseFilePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SoftwarePkgSrcRPM is the content of the src.rpm of pkg.
+type SoftwarePkgSrcRPM struct {
+	Files []SrcRPMFile
+
+	// Spec is parsed from the spec file embedded in the src.rpm.
+	// It is nil if there is no spec file.
+	Spec *SoftwarePkgSpec
+}
+
+func (srpm *SoftwarePkgSrcRPM) filter(f func(*SrcRPMFile) bool) []string {
+	var r []string
+
+	for i := range srpm.Files {
+		if f(&srpm.Files[i]) {
+			r = append(r, srpm.Files[i].Path)
+		}
+	}
+
+	return r
+}
+
+// LicenseFiles returns the paths of the license files.
+func (srpm *SoftwarePkgSrcRPM) LicenseFiles() []string {
+	return srpm.filter(func(f *SrcRPMFile) bool { return f.isLicense() })
+}
+
+// BundledBinaries returns the paths of the binaries which are not built from the source.
+func (srpm *SoftwarePkgSrcRPM) BundledBinaries() []string {
+	return srpm.filter(func(f *SrcRPMFile) bool { return f.IsBinary })
+}
+
+// SrcRPMReviewNotes returns the findings of the src.rpm which the reviewers
+// should pay attention to.
+func (entity *SoftwarePkgBasicInfo) SrcRPMReviewNotes(srpm *SoftwarePkgSrcRPM) []string {
+	var notes []string
+
+	if srpm.Spec == nil {
+		notes = append(notes, "There is no spec file in the src.rpm.")
+	} else if diff := diffSpec(srpm.Spec, &entity.Spec); len(diff) > 0 {
+		notes = append(notes, fmt.Sprintf(
+			"The spec in the src.rpm doesn't match the one of spec url: %s.",
+			strings.Join(diff, "; "),
+		))
+	}
+
+	if v := srpm.BundledBinaries(); len(v) > 0 {
+		notes = append(notes, fmt.Sprintf(
+			"Found %d bundled binaries in the src.rpm: %s. Please make sure they can be built from the source.",
+			len(v), summarizeFiles(v),
+		))
+	}
+
+	if v := srpm.LicenseFiles(); len(v) > 0 {
+		notes = append(notes, fmt.Sprintf(
+			"Found %d license files in the src.rpm: %s.", len(v), summarizeFiles(v),
+		))
+	} else {
+		notes = append(notes, "No license file is found in the src.rpm.")
+	}
+
+	return notes
+}
+
+// SetSrcRPMNotes replaces the findings of the src.rpm at the url. It returns
+// false if nothing is changed, including the case that the src.rpm has been
+// updated since it was inspected.
+func (entity *SoftwarePkgBasicInfo) SetSrcRPMNotes(srcRPMURL dp.URL, notes []string) bool {
+	if entity.Application.SourceCode.SrcRPMURL.URL() != srcRPMURL.URL() {
+		return false
+	}
+
+	if strings.Join(entity.SrcRPMNotes, "\n") == strings.Join(notes, "\n") {
+		return false
+	}
+
+	entity.SrcRPMNotes = notes
+
+	return true
+}
+
+// diffSpec compares the spec in the src.rpm with the one of spec url.
+// It is skipped if the spec of spec url was not parsed.
+func diffSpec(inRPM, spec *SoftwarePkgSpec) []string {
+	if spec.Name == "" {
+		return nil
+	}
+
+	var r []string
+
+	compare := func(field, a, b string) {
+		if a != b {
+			r = append(r, fmt.Sprintf("%s: %s in the src.rpm but %s in spec url", field, a, b))
+		}
+	}
+
+	compare("name", inRPM.Name, spec.Name)
+	compare("version", inRPM.Version, spec.Version)
+	compare("license", inRPM.License, spec.License)
+	compare("sources", baseNames(inRPM.Sources), baseNames(spec.Sources))
+	compare("patches", baseNames(inRPM.Patches), baseNames(spec.Patches))
+
+	return r
+}
+
+// baseNames joins the base names of the sources, because the source
+// may be an url in the spec.
+func baseNames(v []string) string {
+	r := make([]string, len(v))
+	for i := range v {
+		r[i] = path.Base(v[i])
+	}
+
+	return strings.Join(r, ",")
+}
+
+func summarizeFiles(v []string) string {
+	if len(v) <= maxFilesInNote {
+		return strings.Join(v, ", ")
+	}
+
+	return fmt.Sprintf(
+		"%s and %d more", strings.Join(v[:maxFilesInNote], ", "), len(v)-maxFilesInNote,
+	)
+}
@@ -0,0 +1,11 @@
+package specparser
+
+import (
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+type SpecParser interface {
+	Parse(dp.URL) (domain.SoftwarePkgSpec, error)
+	ParseContent([]byte) (domain.SoftwarePkgSpec, error)
+}
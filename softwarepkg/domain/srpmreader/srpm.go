@@ -0,0 +1,10 @@
+package srpmreader
+
+import (
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+type SrcRPMReader interface {
+	Read(dp.URL) (domain.SoftwarePkgSrcRPM, error)
+}
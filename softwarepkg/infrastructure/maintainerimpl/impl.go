@@ -66,26 +66,28 @@ func (impl *maintainerImpl) HasPermission(info *domain.SoftwarePkgBasicInfo, use
 }
 
 func (impl *maintainerImpl) Reviewer(info *domain.SoftwarePkgBasicInfo, user *domain.User) domain.Reviewer {
+	r := domain.Reviewer{
+		User: user.Account,
+	}
+
 	v := impl.agent.GetData()
 
 	m, ok := v.(*sigData)
 	if !ok {
-		return domain.Reviewer{}
-	}
-
-	r := domain.Reviewer{
-		User: user.Account,
+		return r
 	}
 
 	if m.isSigMaintainer(user.GiteeID, impl.tcSig) {
-		r.Role = append(r.Role, "tc")
+		r.Role = append(r.Role, dp.CommunityRoleTC)
 	}
 
 	if m.isSigMaintainer(user.GiteeID, info.Sig()) {
-		r.Role = append(r.Role, "sig maintainer")
+		r.Role = append(r.Role, dp.CommunityRoleSigMaintainer)
 	}
 
-	// TODO committer
+	if m.isSigCommitter(user.GiteeID, info.Sig()) {
+		r.Role = append(r.Role, dp.CommunityRoleCommitter)
+	}
 
 	return r
 }
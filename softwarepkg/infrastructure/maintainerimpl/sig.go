@@ -9,41 +9,60 @@ import (
 	"github.com/opensourceways/server-common-lib/utils"
 )
 
-type sigMaintainers map[string]bool
+type sigUsers map[string]bool
 
-func (m sigMaintainers) isMaintainer(user string) bool {
+func (m sigUsers) has(user string) bool {
 	return m != nil && m[user]
 }
 
-func newSigMaintainers(maintainers []string) sigMaintainers {
-	r := make(sigMaintainers, len(maintainers))
+func newSigUsers(users []string) sigUsers {
+	r := make(sigUsers, len(users))
 
-	for i := range maintainers {
-		r[maintainers[i]] = true
+	for i := range users {
+		r[users[i]] = true
 	}
 
 	return r
 }
 
+// sigMembers
+type sigMembers struct {
+	committers  sigUsers
+	maintainers sigUsers
+}
+
 // sigData
 type sigData struct {
 	Data []struct {
+		Committers  []string `json:"committers"`
 		Maintainers []string `json:"maintainers"`
 		SigName     string   `json:"sig_name"`
 	} `json:"data"`
 
-	maintainers map[string]sigMaintainers
-	md5sum      string
+	members map[string]sigMembers
+	md5sum  string
+}
+
+func (s *sigData) sigMembers(sig string) (sigMembers, bool) {
+	if s == nil || s.members == nil {
+		return sigMembers{}, false
+	}
+
+	v, ok := s.members[sig]
+
+	return v, ok
 }
 
 func (s *sigData) isSigMaintainer(user, sig string) bool {
-	if s != nil && s.maintainers != nil {
-		v, ok := s.maintainers[sig]
+	v, ok := s.sigMembers(sig)
 
-		return ok && v.isMaintainer(user)
-	}
+	return ok && v.maintainers.has(user)
+}
+
+func (s *sigData) isSigCommitter(user, sig string) bool {
+	v, ok := s.sigMembers(sig)
 
-	return false
+	return ok && v.committers.has(user)
 }
 
 func (s *sigData) init(md5sum string) {
@@ -54,10 +73,13 @@ func (s *sigData) init(md5sum string) {
 	}
 
 	items := s.Data
-	s.maintainers = make(map[string]sigMaintainers, len(items))
+	s.members = make(map[string]sigMembers, len(items))
 
 	for i := range items {
-		s.maintainers[items[i].SigName] = newSigMaintainers(items[i].Maintainers)
+		s.members[items[i].SigName] = sigMembers{
+			committers:  newSigUsers(items[i].Committers),
+			maintainers: newSigUsers(items[i].Maintainers),
+		}
 	}
 }
 
@@ -5,6 +5,9 @@ type Config struct {
 }
 
 type Table struct {
+	Outbox             string `json:"outbox"                required:"true"`
+	Review             string `json:"review"                required:"true"`
+	Revision           string `json:"revision"              required:"true"`
 	OperationLog       string `json:"operation_log"          required:"true"`
 	ReviewComment      string `json:"review_comment"        required:"true"`
 	SoftwarePkgBasic   string `json:"software_pkg_basic"    required:"true"`
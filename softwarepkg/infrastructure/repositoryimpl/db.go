@@ -12,4 +12,6 @@ type dbClient interface {
 
 	IsRowNotFound(error) bool
 	IsRowExists(error) bool
+
+	WithTx(postgresql.Tx) postgresql.TxTable
 }
@@ -9,13 +9,43 @@ type operationLog struct {
 	commentDBCli dbClient
 }
 
-func (t operationLog) AddOperationLog(v *domain.SoftwarePkgOperationLog) error {
+func (t operationLog) addOperationLog(cli postgresql.TxTable, v *domain.SoftwarePkgOperationLog) error {
 	var do operationLogDO
-	t.toOperationLogDO(v, &do)
+	if err := t.toOperationLogDO(v, &do); err != nil {
+		return err
+	}
 
 	filter := operationLogDO{Id: do.Id}
 
-	return t.commentDBCli.Insert(&filter, &do)
+	if err := cli.Insert(&filter, &do); err != nil {
+		return err
+	}
+
+	v.Id = do.Id.String()
+
+	return nil
+}
+
+// addOperationLogs saves the logs of pkg which have not been saved in the transaction.
+func (t operationLog) addOperationLogs(tx postgresql.Tx, pkg *domain.SoftwarePkgBasicInfo) error {
+	cli := t.commentDBCli.WithTx(tx)
+
+	for i := range pkg.Logs {
+		item := &pkg.Logs[i]
+		if item.Id != "" {
+			continue
+		}
+
+		if item.PkgId == "" {
+			item.PkgId = pkg.Id
+		}
+
+		if err := t.addOperationLog(cli, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (t operationLog) findOperationLogs(pid string) ([]domain.SoftwarePkgOperationLog, error) {
@@ -1,6 +1,8 @@
 package repositoryimpl
 
 import (
+	"encoding/json"
+
 	"github.com/google/uuid"
 
 	"github.com/opensourceways/software-package-server/softwarepkg/domain"
@@ -13,17 +15,56 @@ type operationLogDO struct {
 	User      string    `gorm:"column:user"`
 	PkgId     string    `gorm:"column:software_pkg_id"`
 	Action    string    `gorm:"column:action"`
+	Details   string    `gorm:"column:details"`
 	CreatedAt int64     `gorm:"column:created_at"`
 }
 
-func (t operationLog) toOperationLogDO(v *domain.SoftwarePkgOperationLog, do *operationLogDO) {
+// operationLogDetailsDO
+type operationLogDetailsDO struct {
+	OldPhase string `json:"old_phase,omitempty"`
+	NewPhase string `json:"new_phase,omitempty"`
+	CIPRNum  int    `json:"ci_pr_num,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+func (t operationLog) toOperationLogDO(v *domain.SoftwarePkgOperationLog, do *operationLogDO) error {
+	details, err := toOperationLogDetailsDO(&v.Details)
+	if err != nil {
+		return err
+	}
+
 	*do = operationLogDO{
 		Id:        uuid.New(),
 		User:      v.User.Account(),
 		PkgId:     v.PkgId,
 		Action:    v.Action.PackageOperationLogAction(),
+		Details:   details,
 		CreatedAt: v.Time,
 	}
+
+	return nil
+}
+
+func toOperationLogDetailsDO(v *domain.SoftwarePkgOperationLogDetails) (string, error) {
+	do := operationLogDetailsDO{
+		CIPRNum: v.CIPRNum,
+		Reason:  v.Reason,
+	}
+
+	if v.OldPhase != nil {
+		do.OldPhase = v.OldPhase.PackagePhase()
+	}
+
+	if v.NewPhase != nil {
+		do.NewPhase = v.NewPhase.PackagePhase()
+	}
+
+	b, err := json.Marshal(do)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
 }
 
 func (do *operationLogDO) toSoftwarePkgOperationLog() (v domain.SoftwarePkgOperationLog, err error) {
@@ -32,9 +73,40 @@ func (do *operationLogDO) toSoftwarePkgOperationLog() (v domain.SoftwarePkgOpera
 		return
 	}
 
+	if v.Details, err = do.toOperationLogDetails(); err != nil {
+		return
+	}
+
 	v.Time = do.CreatedAt
 	v.Action = dp.NewPackageOperationLogAction(do.Action)
 	v.PkgId = do.PkgId
 
 	return
 }
+
+func (do *operationLogDO) toOperationLogDetails() (v domain.SoftwarePkgOperationLogDetails, err error) {
+	if do.Details == "" {
+		// the log was saved before the details were introduced
+		return
+	}
+
+	var details operationLogDetailsDO
+	if err = json.Unmarshal([]byte(do.Details), &details); err != nil {
+		return
+	}
+
+	v.CIPRNum = details.CIPRNum
+	v.Reason = details.Reason
+
+	if details.OldPhase != "" {
+		if v.OldPhase, err = dp.NewPackagePhase(details.OldPhase); err != nil {
+			return
+		}
+	}
+
+	if details.NewPhase != "" {
+		v.NewPhase, err = dp.NewPackagePhase(details.NewPhase)
+	}
+
+	return
+}
@@ -0,0 +1,83 @@
+package repositoryimpl
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/opensourceways/software-package-server/common/infrastructure/postgresql"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/repository"
+	"github.com/opensourceways/software-package-server/utils"
+)
+
+func NewSoftwarePkgOutbox(cfg *Config) repository.SoftwarePkgOutbox {
+	return outbox{postgresql.NewDBTable(cfg.Table.Outbox)}
+}
+
+type outbox struct {
+	outboxDBCli dbClient
+}
+
+// addEvents saves the events of pkg in the transaction
+func (t outbox) addEvents(tx postgresql.Tx, pkg *domain.SoftwarePkgBasicInfo) error {
+	cli := t.outboxDBCli.WithTx(tx)
+	now := utils.Now()
+
+	for _, event := range pkg.Events {
+		body, err := pkg.EventMessage(event)
+		if err != nil {
+			return err
+		}
+
+		do := outboxEventDO{
+			Id:        uuid.New(),
+			PkgId:     pkg.Id,
+			Event:     event,
+			Body:      string(body),
+			CreatedAt: now,
+		}
+
+		if err := cli.Insert(&outboxEventDO{Id: do.Id}, &do); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t outbox) FindPendingEvents(events []string, num int) ([]repository.OutboxEvent, error) {
+	var dos []outboxEventDO
+
+	err := t.outboxDBCli.GetRecords(
+		[]postgresql.ColumnFilter{
+			postgresql.NewEqualFilter(fieldPublishedAt, 0),
+			postgresql.NewInFilter(fieldEvent, events),
+		},
+		&dos,
+		postgresql.Pagination{CountPerPage: num},
+		[]postgresql.SortByColumn{
+			{Column: fieldSeq, Ascend: true},
+		},
+	)
+	if err != nil || len(dos) == 0 {
+		return nil, err
+	}
+
+	v := make([]repository.OutboxEvent, len(dos))
+	for i := range dos {
+		v[i] = dos[i].toOutboxEvent()
+	}
+
+	return v, nil
+}
+
+func (t outbox) MarkEventPublished(id string) error {
+	v, err := uuid.Parse(id)
+	if err != nil {
+		return err
+	}
+
+	return t.outboxDBCli.UpdateRecord(
+		&outboxEventDO{Id: v},
+		map[string]any{fieldPublishedAt: utils.Now()},
+	)
+}
@@ -0,0 +1,34 @@
+package repositoryimpl
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/repository"
+)
+
+const (
+	fieldSeq         = "seq"
+	fieldEvent       = "event"
+	fieldPublishedAt = "published_at"
+)
+
+type outboxEventDO struct {
+	// must set "uuid" as the name of column
+	Id    uuid.UUID `gorm:"column:uuid;type:uuid"`
+	Seq   int64     `gorm:"column:seq;autoIncrement"`
+	PkgId string    `gorm:"column:software_pkg_id"`
+	Event string    `gorm:"column:event"`
+	Body  string    `gorm:"column:body"`
+	// PublishedAt is 0 if the event is not published
+	PublishedAt int64 `gorm:"column:published_at"`
+	CreatedAt   int64 `gorm:"column:created_at"`
+}
+
+func (do *outboxEventDO) toOutboxEvent() repository.OutboxEvent {
+	return repository.OutboxEvent{
+		Id:    do.Id.String(),
+		PkgId: do.PkgId,
+		Event: do.Event,
+		Body:  []byte(do.Body),
+	}
+}
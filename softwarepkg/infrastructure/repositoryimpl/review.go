@@ -0,0 +1,77 @@
+package repositoryimpl
+
+import (
+	"github.com/opensourceways/software-package-server/common/infrastructure/postgresql"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+)
+
+type review struct {
+	reviewDBCli dbClient
+}
+
+// saveUserReviews saves all the reviews of pkg in the transaction if they
+// are changed. The previous review of a reviewer will be replaced.
+func (t review) saveUserReviews(tx postgresql.Tx, pkg *domain.SoftwarePkgBasicInfo) error {
+	if !pkg.ReviewsChanged {
+		return nil
+	}
+
+	cli := t.reviewDBCli.WithTx(tx)
+
+	for i := range pkg.Review.Reviews {
+		if err := t.saveUserReview(cli, pkg.Id, &pkg.Review.Reviews[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t review) saveUserReview(cli postgresql.TxTable, pid string, v *domain.UserReview) error {
+	var do SoftwarePkgReviewDO
+	if err := t.toSoftwarePkgReviewDO(pid, v, &do); err != nil {
+		return err
+	}
+
+	filter := SoftwarePkgReviewDO{PkgId: do.PkgId, Reviewer: do.Reviewer}
+
+	err := cli.Insert(&filter, &do)
+	if err == nil || !cli.IsRowExists(err) {
+		return err
+	}
+
+	// the reviewer has reviewed before, replace it
+	m, err := do.toMapForUpdate()
+	if err != nil {
+		return err
+	}
+
+	return cli.UpdateRecord(&filter, m)
+}
+
+func (t review) findUserReviews(pid string) ([]domain.UserReview, error) {
+	var dos []SoftwarePkgReviewDO
+
+	err := t.reviewDBCli.GetRecords(
+		[]postgresql.ColumnFilter{
+			postgresql.NewEqualFilter(fieldSoftwarePkgId, pid),
+		},
+		&dos,
+		postgresql.Pagination{},
+		[]postgresql.SortByColumn{
+			{Column: fieldCreatedAt, Ascend: true},
+		},
+	)
+	if err != nil || len(dos) == 0 {
+		return nil, err
+	}
+
+	v := make([]domain.UserReview, len(dos))
+	for i := range dos {
+		if v[i], err = dos[i].toUserReview(); err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
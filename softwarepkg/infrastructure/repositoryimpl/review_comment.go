@@ -12,13 +12,45 @@ type reviewComment struct {
 	commentDBCli dbClient
 }
 
-func (t reviewComment) AddReviewComment(pid string, comment *domain.SoftwarePkgReviewComment) error {
+// AddReviewComments adds the comments in a transaction.
+func (t reviewComment) AddReviewComments(pid string, comments []domain.SoftwarePkgReviewComment) error {
+	return postgresql.Transaction(func(tx postgresql.Tx) error {
+		return t.addReviewComments(t.commentDBCli.WithTx(tx), pid, comments)
+	})
+}
+
+func (t reviewComment) addReviewComments(
+	cli postgresql.TxTable, pid string, comments []domain.SoftwarePkgReviewComment,
+) error {
+	for i := range comments {
+		if err := t.addReviewComment(cli, pid, &comments[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t reviewComment) addReviewComment(
+	cli postgresql.TxTable, pid string, comment *domain.SoftwarePkgReviewComment,
+) error {
 	var do SoftwarePkgReviewCommentDO
 	t.toSoftwarePkgReviewCommentDO(pid, comment, &do)
 
 	filter := SoftwarePkgReviewCommentDO{Id: do.Id}
 
-	return t.commentDBCli.Insert(&filter, &do)
+	if err := cli.Insert(&filter, &do); err != nil {
+		return err
+	}
+
+	comment.Id = do.Id.String()
+
+	return nil
+}
+
+// addNewComments saves the new comments of pkg in the transaction.
+func (t reviewComment) addNewComments(tx postgresql.Tx, pkg *domain.SoftwarePkgBasicInfo) error {
+	return t.addReviewComments(t.commentDBCli.WithTx(tx), pkg.Id, pkg.NewComments)
 }
 
 func (t reviewComment) findReviewComments(pid string) (
@@ -0,0 +1,143 @@
+package repositoryimpl
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+	"github.com/opensourceways/software-package-server/utils"
+)
+
+const (
+	fieldRoles    = "roles"
+	fieldItems    = "items"
+	fieldReviewer = "reviewer"
+)
+
+type SoftwarePkgReviewDO struct {
+	// must set "uuid" as the name of column
+	Id        uuid.UUID      `gorm:"column:uuid;type:uuid"`
+	PkgId     string         `gorm:"column:software_pkg_id"`
+	Reviewer  string         `gorm:"column:reviewer"`
+	Roles     pq.StringArray `gorm:"column:roles;type:text[];default:'{}'"`
+	Items     string         `gorm:"column:items"`
+	CreatedAt int64          `gorm:"column:created_at"`
+	UpdatedAt int64          `gorm:"column:updated_at"`
+}
+
+type checkItemReviewDO struct {
+	Index      int    `json:"index"`
+	Pass       bool   `json:"pass"`
+	Desc       string `json:"desc"`
+	Stale      bool   `json:"stale,omitempty"`
+	Revision   int    `json:"revision"`
+	ReviewedAt int64  `json:"reviewed_at,omitempty"`
+}
+
+func (t review) toSoftwarePkgReviewDO(
+	pid string, v *domain.UserReview, do *SoftwarePkgReviewDO,
+) error {
+	items := make([]checkItemReviewDO, len(v.Items))
+	for i := range v.Items {
+		item := &v.Items[i]
+
+		items[i] = checkItemReviewDO{
+			Index:      item.Index,
+			Pass:       item.Pass,
+			Desc:       item.Desc,
+			Stale:      item.Stale,
+			Revision:   item.Revision,
+			ReviewedAt: item.ReviewedAt,
+		}
+	}
+
+	b, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	now := utils.Now()
+
+	*do = SoftwarePkgReviewDO{
+		Id:        uuid.New(),
+		PkgId:     pid,
+		Reviewer:  v.User.Account(),
+		Roles:     toCommunityRolesDO(v.Role),
+		Items:     string(b),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	return nil
+}
+
+func (do *SoftwarePkgReviewDO) toMapForUpdate() (map[string]any, error) {
+	roles, err := marshalStringArray(do.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		fieldRoles:     roles,
+		fieldItems:     do.Items,
+		fieldUpdatedAt: do.UpdatedAt,
+	}, nil
+}
+
+func (do *SoftwarePkgReviewDO) toUserReview() (r domain.UserReview, err error) {
+	if r.User, err = dp.NewAccount(do.Reviewer); err != nil {
+		return
+	}
+
+	if r.Role, err = toCommunityRoles(do.Roles); err != nil {
+		return
+	}
+
+	var items []checkItemReviewDO
+	if err = json.Unmarshal([]byte(do.Items), &items); err != nil {
+		return
+	}
+
+	r.Items = make([]domain.CheckItemReview, len(items))
+	for i := range items {
+		item := &items[i]
+
+		r.Items[i] = domain.CheckItemReview{
+			Index:      item.Index,
+			Pass:       item.Pass,
+			Desc:       item.Desc,
+			Stale:      item.Stale,
+			Revision:   item.Revision,
+			ReviewedAt: item.ReviewedAt,
+		}
+	}
+
+	return
+}
+
+func toCommunityRolesDO(v []dp.CommunityRole) []string {
+	r := make([]string, len(v))
+	for i := range v {
+		r[i] = v[i].CommunityRole()
+	}
+
+	return r
+}
+
+func toCommunityRoles(v []string) (r []dp.CommunityRole, err error) {
+	if len(v) == 0 {
+		return
+	}
+
+	r = make([]dp.CommunityRole, len(v))
+	for i := range v {
+		if r[i], err = dp.NewCommunityRole(v[i]); err != nil {
+			return
+		}
+	}
+
+	return
+}
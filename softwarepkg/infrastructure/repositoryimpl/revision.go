@@ -0,0 +1,53 @@
+package repositoryimpl
+
+import (
+	"github.com/opensourceways/software-package-server/common/infrastructure/postgresql"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+)
+
+type revision struct {
+	revisionDBCli dbClient
+}
+
+// addNewRevisions saves the new revisions of pkg in the transaction.
+func (t revision) addNewRevisions(tx postgresql.Tx, pkg *domain.SoftwarePkgBasicInfo) error {
+	cli := t.revisionDBCli.WithTx(tx)
+
+	for i := range pkg.NewRevisions {
+		var do revisionDO
+		t.toRevisionDO(pkg.Id, &pkg.NewRevisions[i], &do)
+
+		if err := cli.Insert(&revisionDO{Id: do.Id}, &do); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t revision) FindRevisions(pid string) ([]domain.SoftwarePkgRevision, error) {
+	var dos []revisionDO
+
+	err := t.revisionDBCli.GetRecords(
+		[]postgresql.ColumnFilter{
+			postgresql.NewEqualFilter(fieldSoftwarePkgId, pid),
+		},
+		&dos,
+		postgresql.Pagination{},
+		[]postgresql.SortByColumn{
+			{Column: fieldNumber, Ascend: true},
+		},
+	)
+	if err != nil || len(dos) == 0 {
+		return nil, err
+	}
+
+	v := make([]domain.SoftwarePkgRevision, len(dos))
+	for i := range dos {
+		if v[i], err = dos[i].toSoftwarePkgRevision(); err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
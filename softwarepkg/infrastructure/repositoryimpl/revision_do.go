@@ -0,0 +1,95 @@
+package repositoryimpl
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+const fieldNumber = "number"
+
+type revisionDO struct {
+	// must set "uuid" as the name of column
+	Id              uuid.UUID `gorm:"column:uuid;type:uuid"`
+	PkgId           string    `gorm:"column:software_pkg_id"`
+	Number          int       `gorm:"column:number"`
+	Author          string    `gorm:"column:author"`
+	Sig             string    `gorm:"column:sig"`
+	SpecURL         string    `gorm:"column:spec_url"`
+	Upstream        string    `gorm:"column:upstream"`
+	SrcRPMURL       string    `gorm:"column:src_rpm_url"`
+	PackageDesc     string    `gorm:"column:package_desc"`
+	ReasonToImport  string    `gorm:"column:reason_to_import"`
+	PackagePlatform string    `gorm:"column:package_platform"`
+	License         string    `gorm:"column:license"`
+	CreatedAt       int64     `gorm:"column:created_at"`
+}
+
+func (t revision) toRevisionDO(pid string, v *domain.SoftwarePkgRevision, do *revisionDO) {
+	app := &v.Application
+
+	*do = revisionDO{
+		Id:              uuid.New(),
+		PkgId:           pid,
+		Number:          v.Number,
+		Author:          v.Author.Account(),
+		Sig:             app.ImportingPkgSig.ImportingPkgSig(),
+		SpecURL:         app.SourceCode.SpecURL.URL(),
+		Upstream:        app.SourceCode.Upstream.URL(),
+		SrcRPMURL:       app.SourceCode.SrcRPMURL.URL(),
+		PackageDesc:     app.PackageDesc.PackageDesc(),
+		ReasonToImport:  app.ReasonToImportPkg.ReasonToImportPkg(),
+		PackagePlatform: app.PackagePlatform.PackagePlatform(),
+		CreatedAt:       v.CreatedAt,
+	}
+
+	if app.License != nil {
+		do.License = app.License.License()
+	}
+}
+
+func (do *revisionDO) toSoftwarePkgRevision() (v domain.SoftwarePkgRevision, err error) {
+	if v.Author, err = dp.NewAccount(do.Author); err != nil {
+		return
+	}
+
+	v.Number = do.Number
+	v.CreatedAt = do.CreatedAt
+
+	app := &v.Application
+
+	if app.ReasonToImportPkg, err = dp.NewReasonToImportPkg(do.ReasonToImport); err != nil {
+		return
+	}
+
+	if app.PackageDesc, err = dp.NewPackageDesc(do.PackageDesc); err != nil {
+		return
+	}
+
+	if app.PackagePlatform, err = dp.NewPackagePlatform(do.PackagePlatform); err != nil {
+		return
+	}
+
+	if app.ImportingPkgSig, err = dp.NewImportingPkgSig(do.Sig); err != nil {
+		return
+	}
+
+	if app.SourceCode.SrcRPMURL, err = dp.NewURL(do.SrcRPMURL); err != nil {
+		return
+	}
+
+	if app.SourceCode.Upstream, err = dp.NewURL(do.Upstream); err != nil {
+		return
+	}
+
+	if do.License != "" {
+		if app.License, err = dp.NewLicense(do.License); err != nil {
+			return
+		}
+	}
+
+	app.SourceCode.SpecURL, err = dp.NewURL(do.SpecURL)
+
+	return
+}
@@ -10,11 +10,17 @@ import (
 type softwarePkgImpl struct {
 	softwarePkgBasic
 
+	review
+
 	reviewComment
 
 	operationLog
 
 	translationComment
+
+	outbox
+
+	revision
 }
 
 func NewSoftwarePkg(cfg *Config) repository.SoftwarePkg {
@@ -22,6 +28,9 @@ func NewSoftwarePkg(cfg *Config) repository.SoftwarePkg {
 		softwarePkgBasic: softwarePkgBasic{
 			postgresql.NewDBTable(cfg.Table.SoftwarePkgBasic),
 		},
+		review: review{
+			postgresql.NewDBTable(cfg.Table.Review),
+		},
 		reviewComment: reviewComment{
 			postgresql.NewDBTable(cfg.Table.ReviewComment),
 		},
@@ -31,13 +40,90 @@ func NewSoftwarePkg(cfg *Config) repository.SoftwarePkg {
 		operationLog: operationLog{
 			postgresql.NewDBTable(cfg.Table.OperationLog),
 		},
+		outbox: outbox{
+			postgresql.NewDBTable(cfg.Table.Outbox),
+		},
+		revision: revision{
+			postgresql.NewDBTable(cfg.Table.Revision),
+		},
 	}
 }
 
+// AddSoftwarePkg adds the pkg with its logs, comments and events in a transaction.
+func (impl softwarePkgImpl) AddSoftwarePkg(pkg *domain.SoftwarePkgBasicInfo) error {
+	return impl.saveInTx(pkg, func(tx postgresql.Tx) error {
+		cli := impl.softwarePkgBasic.basicDBCli.WithTx(tx)
+
+		return impl.softwarePkgBasic.addSoftwarePkg(cli, pkg)
+	})
+}
+
+// SaveSoftwarePkg saves the pkg with its logs, comments and events in a transaction.
+func (impl softwarePkgImpl) SaveSoftwarePkg(pkg *domain.SoftwarePkgBasicInfo, version int) error {
+	return impl.saveInTx(pkg, func(tx postgresql.Tx) error {
+		cli := impl.softwarePkgBasic.basicDBCli.WithTx(tx)
+
+		return impl.softwarePkgBasic.saveSoftwarePkg(cli, pkg, version)
+	})
+}
+
+// saveInTx is the unit of work of saving a pkg. The pkg is saved by save
+// first and then the changed reviews and the new logs, comments, revisions and
+// events of it are saved in the same transaction, so either all of them are
+// saved or none of them.
+func (impl softwarePkgImpl) saveInTx(
+	pkg *domain.SoftwarePkgBasicInfo, save func(postgresql.Tx) error,
+) error {
+	err := postgresql.Transaction(func(tx postgresql.Tx) error {
+		if err := save(tx); err != nil {
+			return err
+		}
+
+		if err := impl.review.saveUserReviews(tx, pkg); err != nil {
+			return err
+		}
+
+		if err := impl.operationLog.addOperationLogs(tx, pkg); err != nil {
+			return err
+		}
+
+		if err := impl.reviewComment.addNewComments(tx, pkg); err != nil {
+			return err
+		}
+
+		if err := impl.revision.addNewRevisions(tx, pkg); err != nil {
+			return err
+		}
+
+		return impl.outbox.addEvents(tx, pkg)
+	})
+	if err == nil {
+		pkg.NewComments = nil
+		pkg.NewRevisions = nil
+		pkg.Events = nil
+		pkg.ReviewsChanged = false
+	}
+
+	return err
+}
+
+func (impl softwarePkgImpl) FindSoftwarePkgBasicInfo(pid string) (
+	info domain.SoftwarePkgBasicInfo, version int, err error,
+) {
+	info, version, err = impl.softwarePkgBasic.FindSoftwarePkgBasicInfo(pid)
+	if err != nil {
+		return
+	}
+
+	info.Review.Reviews, err = impl.findUserReviews(pid)
+
+	return
+}
+
 func (impl softwarePkgImpl) FindSoftwarePkg(pid string) (
 	pkg domain.SoftwarePkg, version int, err error,
 ) {
-	pkg.SoftwarePkgBasicInfo, version, err = impl.softwarePkgBasic.FindSoftwarePkgBasicInfo(pid)
+	pkg.SoftwarePkgBasicInfo, version, err = impl.FindSoftwarePkgBasicInfo(pid)
 	if err != nil {
 		return
 	}
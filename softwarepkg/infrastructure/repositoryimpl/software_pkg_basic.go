@@ -17,6 +17,12 @@ type softwarePkgBasic struct {
 }
 
 func (s softwarePkgBasic) SaveSoftwarePkg(pkg *domain.SoftwarePkgBasicInfo, version int) error {
+	return s.saveSoftwarePkg(s.basicDBCli, pkg, version)
+}
+
+func (s softwarePkgBasic) saveSoftwarePkg(
+	cli postgresql.TxTable, pkg *domain.SoftwarePkgBasicInfo, version int,
+) error {
 	filter := map[string]any{
 		fieldId:      pkg.Id,
 		fieldVersion: version,
@@ -34,8 +40,8 @@ func (s softwarePkgBasic) SaveSoftwarePkg(pkg *domain.SoftwarePkgBasicInfo, vers
 		return err
 	}
 
-	err = s.basicDBCli.UpdateRecord(filter, v)
-	if err != nil && s.basicDBCli.IsRowNotFound(err) {
+	err = cli.UpdateRecord(filter, v)
+	if err != nil && cli.IsRowNotFound(err) {
 		return commonrepo.NewErrorConcurrentUpdating(err)
 	}
 
@@ -83,6 +89,12 @@ func (s softwarePkgBasic) FindSoftwarePkgs(pkgs repository.OptToFindSoftwarePkgs
 		)
 	}
 
+	if pkgs.CIStatus != nil {
+		filter = append(filter,
+			postgresql.NewEqualFilter(fieldCIStatus, pkgs.CIStatus.PackageCIStatus()),
+		)
+	}
+
 	if pkgs.Platform != nil {
 		filter = append(filter,
 			postgresql.NewEqualFilter(fieldPackagePlatform, pkgs.Platform.PackagePlatform()),
@@ -125,7 +137,97 @@ func (s softwarePkgBasic) FindSoftwarePkgs(pkgs repository.OptToFindSoftwarePkgs
 	return
 }
 
+func (s softwarePkgBasic) FindSoftwarePkgsByNames(names []dp.PackageName) (
+	r []domain.SoftwarePkgBasicInfo, err error,
+) {
+	v := make([]string, len(names))
+	for i := range names {
+		v[i] = names[i].PackageName()
+	}
+
+	var dos []SoftwarePkgBasicDO
+
+	err = s.basicDBCli.GetRecords(
+		[]postgresql.ColumnFilter{
+			postgresql.NewInFilter(fieldPackageName, v),
+		},
+		&dos,
+		postgresql.Pagination{},
+		[]postgresql.SortByColumn{
+			{Column: fieldAppliedAt},
+		},
+	)
+	if err != nil || len(dos) == 0 {
+		return
+	}
+
+	r = make([]domain.SoftwarePkgBasicInfo, len(dos))
+	for i := range dos {
+		if r[i], err = dos[i].toSoftwarePkgBasicInfo(); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s softwarePkgBasic) FindSimilarSoftwarePkgCandidates(key domain.SimilarityKey) (
+	r []domain.SoftwarePkgBasicInfo, err error,
+) {
+	phases := []string{
+		dp.PackagePhaseReviewing.PackagePhase(),
+		dp.PackagePhaseChangesRequested.PackagePhase(),
+		dp.PackagePhaseApproved.PackagePhase(),
+		dp.PackagePhaseCreatingRepo.PackagePhase(),
+		dp.PackagePhaseImported.PackagePhase(),
+	}
+
+	d := key.MaxNameDistance()
+	n := key.NameLen()
+
+	// the pkgs saved before the keys were introduced have no keys,
+	// so they are always the candidates.
+	keys := []postgresql.ColumnFilter{
+		postgresql.NewEqualFilter(fieldNameKey, ""),
+		postgresql.NewBetweenFilter(fieldNameKeyLen, n-d, n+d),
+	}
+
+	if key.Upstream != "" {
+		keys = append(keys, postgresql.NewEqualFilter(fieldUpstreamKey, key.Upstream))
+	}
+
+	var dos []SoftwarePkgBasicDO
+
+	err = s.basicDBCli.GetRecords(
+		[]postgresql.ColumnFilter{
+			postgresql.NewInFilter(fieldPhase, phases),
+			postgresql.NewOrFilter(keys...),
+		},
+		&dos,
+		postgresql.Pagination{},
+		[]postgresql.SortByColumn{
+			{Column: fieldAppliedAt},
+		},
+	)
+	if err != nil || len(dos) == 0 {
+		return
+	}
+
+	r = make([]domain.SoftwarePkgBasicInfo, len(dos))
+	for i := range dos {
+		if r[i], err = dos[i].toSoftwarePkgBasicInfo(); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
 func (s softwarePkgBasic) AddSoftwarePkg(pkg *domain.SoftwarePkgBasicInfo) error {
+	return s.addSoftwarePkg(s.basicDBCli, pkg)
+}
+
+func (s softwarePkgBasic) addSoftwarePkg(cli postgresql.TxTable, pkg *domain.SoftwarePkgBasicInfo) error {
 	var do SoftwarePkgBasicDO
 	if err := s.toSoftwarePkgBasicDO(pkg, &do); err != nil {
 		return err
@@ -133,12 +235,12 @@ func (s softwarePkgBasic) AddSoftwarePkg(pkg *domain.SoftwarePkgBasicInfo) error
 
 	pkg.Id = do.Id.String()
 
-	err := s.basicDBCli.InsertWithNot(
+	err := cli.InsertWithNot(
 		&SoftwarePkgBasicDO{PackageName: do.PackageName},
 		&SoftwarePkgBasicDO{Phase: dp.PackagePhaseClosed.PackagePhase()},
 		&do,
 	)
-	if err != nil && s.basicDBCli.IsRowExists(err) {
+	if err != nil && cli.IsRowExists(err) {
 		err = commonrepo.NewErrorDuplicateCreating(err)
 	}
 
@@ -18,12 +18,16 @@ const (
 	fieldPhase           = "phase"
 	fieldVersion         = "version"
 	fieldImporter        = "importer"
+	fieldCIStatus        = "ci_status"
 	fieldAppliedAt       = "applied_at"
 	fieldUpdatedAt       = "updated_at"
 	fieldApprovedby      = "approvedby"
 	fieldRejectedby      = "rejectedby"
 	fieldPackageName     = "package_name"
 	fieldPackagePlatform = "package_platform"
+	fieldNameKey         = "name_key"
+	fieldNameKeyLen      = "name_key_len"
+	fieldUpstreamKey     = "upstream_key"
 )
 
 func (s softwarePkgBasic) toSoftwarePkgBasicDO(pkg *domain.SoftwarePkgBasicInfo, do *SoftwarePkgBasicDO) (err error) {
@@ -32,7 +36,28 @@ func (s softwarePkgBasic) toSoftwarePkgBasicDO(pkg *domain.SoftwarePkgBasicInfo,
 		return err
 	}
 
+	checkItems, err := toCheckItemsDO(pkg.Review.Items)
+	if err != nil {
+		return err
+	}
+
+	spec, err := toSpecDO(&pkg.Spec)
+	if err != nil {
+		return err
+	}
+
+	licenseCheck, err := toLicenseCheckDO(pkg.Review.LicenseCheck)
+	if err != nil {
+		return err
+	}
+
+	srcRPMNotes, err := toSrcRPMNotesDO(pkg.SrcRPMNotes)
+	if err != nil {
+		return err
+	}
+
 	app := &pkg.Application
+	key := domain.NewSimilarityKey(pkg.PkgName, app.SourceCode.Upstream)
 
 	*do = SoftwarePkgBasicDO{
 		Id:              uuid.New(),
@@ -42,6 +67,7 @@ func (s softwarePkgBasic) toSoftwarePkgBasicDO(pkg *domain.SoftwarePkgBasicInfo,
 		Phase:           pkg.Phase.PackagePhase(),
 		CIPRNum:         pkg.CI.PRNum,
 		CIStatus:        pkg.CI.Status.PackageCIStatus(),
+		CIStartTime:     pkg.CI.StartTime,
 		SpecURL:         app.SourceCode.SpecURL.URL(),
 		SrcRPMURL:       app.SourceCode.SrcRPMURL.URL(),
 		Upstream:        app.SourceCode.Upstream.URL(),
@@ -51,8 +77,21 @@ func (s softwarePkgBasic) toSoftwarePkgBasicDO(pkg *domain.SoftwarePkgBasicInfo,
 		ReasonToImport:  app.ReasonToImportPkg.ReasonToImportPkg(),
 		AppliedAt:       pkg.AppliedAt,
 		UpdatedAt:       pkg.AppliedAt,
+		ClosedAt:        pkg.ClosedAt,
 		ApprovedBy:      toStringArray(pkg.ApprovedBy),
 		RejectedBy:      toStringArray(pkg.RejectedBy),
+		CheckItems:      checkItems,
+		Spec:            spec,
+		LicenseCheck:    licenseCheck,
+		SrcRPMNotes:     srcRPMNotes,
+		NameKey:         key.Name,
+		NameKeyLen:      key.NameLen(),
+		UpstreamKey:     key.Upstream,
+		Revision:        pkg.Revision,
+	}
+
+	if app.License != nil {
+		do.License = app.License.License()
 	}
 
 	if pkg.RepoLink != nil {
@@ -83,9 +122,20 @@ type SoftwarePkgBasicDO struct {
 	ImporterEmail   string                 `gorm:"column:importer_email"                           json:"importer_email"`
 	ReasonToImport  string                 `gorm:"column:reason_to_import"                         json:"reason_to_import"`
 	PackagePlatform string                 `gorm:"column:package_platform"                         json:"package_platform"`
+	CheckItems      string                 `gorm:"column:check_items"                              json:"check_items"`
+	Spec            string                 `gorm:"column:spec"                                     json:"spec"`
+	License         string                 `gorm:"column:license"                                  json:"license"`
+	LicenseCheck    string                 `gorm:"column:license_check"                            json:"license_check"`
+	SrcRPMNotes     string                 `gorm:"column:src_rpm_notes"                            json:"src_rpm_notes"`
+	NameKey         string                 `gorm:"column:name_key;index"                           json:"name_key"`
+	UpstreamKey     string                 `gorm:"column:upstream_key;index"                       json:"upstream_key"`
 	CIPRNum         int                    `gorm:"column:ci_pr_num"                                json:"ci_pr_num"`
+	NameKeyLen      int                    `gorm:"column:name_key_len;index"                       json:"name_key_len"`
+	Revision        int                    `gorm:"column:revision"                                 json:"revision"`
+	CIStartTime     int64                  `gorm:"column:ci_start_time"                            json:"ci_start_time"`
 	AppliedAt       int64                  `gorm:"column:applied_at"                               json:"applied_at"`
 	UpdatedAt       int64                  `gorm:"column:updated_at"                               json:"updated_at"`
+	ClosedAt        int64                  `gorm:"column:closed_at"                                json:"closed_at"`
 	Version         optimisticlock.Version `gorm:"column:version"                                  json:"-"`
 	ApprovedBy      pq.StringArray         `gorm:"column:approvedby;type:text[];default:'{}'"      json:"-"`
 	RejectedBy      pq.StringArray         `gorm:"column:rejectedby;type:text[];default:'{}'"      json:"-"`
@@ -154,6 +204,8 @@ func (do *SoftwarePkgBasicDO) toSoftwarePkgBasicInfo() (info domain.SoftwarePkgB
 	}
 
 	info.AppliedAt = do.AppliedAt
+	info.Revision = do.Revision
+	info.ClosedAt = do.ClosedAt
 
 	if err = do.toSoftwarePkgApplication(&info.Application); err != nil {
 		return
@@ -168,6 +220,23 @@ func (do *SoftwarePkgBasicDO) toSoftwarePkgBasicInfo() (info domain.SoftwarePkgB
 	}
 
 	info.CI.PRNum = do.CIPRNum
+	info.CI.StartTime = do.CIStartTime
+
+	if info.Review.Items, err = do.toCheckItems(); err != nil {
+		return
+	}
+
+	if info.Spec, err = do.toSpec(); err != nil {
+		return
+	}
+
+	if info.Review.LicenseCheck, err = do.toLicenseCheck(); err != nil {
+		return
+	}
+
+	if info.SrcRPMNotes, err = do.toSrcRPMNotes(); err != nil {
+		return
+	}
 
 	info.RejectedBy, err = do.toAccounts(do.RejectedBy)
 
@@ -214,11 +283,84 @@ func (do *SoftwarePkgBasicDO) toSoftwarePkgApplication(app *domain.SoftwarePkgAp
 		return
 	}
 
+	// the pkg applied before the license was introduced has no license.
+	if do.License != "" {
+		if app.License, err = dp.NewLicense(do.License); err != nil {
+			return
+		}
+	}
+
 	app.SourceCode.SpecURL, err = dp.NewURL(do.SpecURL)
 
 	return
 }
 
+// checkItemDO
+type checkItemDO struct {
+	Index  int      `json:"index"`
+	Item   string   `json:"item"`
+	Desc   string   `json:"desc"`
+	Owners []string `json:"owners"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+func toCheckItemsDO(items []domain.CheckItem) (string, error) {
+	if len(items) == 0 {
+		return "", nil
+	}
+
+	v := make([]checkItemDO, len(items))
+	for i := range items {
+		item := &items[i]
+
+		v[i] = checkItemDO{
+			Index:  item.Index,
+			Item:   item.Item,
+			Desc:   item.Desc,
+			Owners: toCommunityRolesDO(item.Owners),
+			Fields: item.Fields,
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (do *SoftwarePkgBasicDO) toCheckItems() ([]domain.CheckItem, error) {
+	if do.CheckItems == "" {
+		return nil, nil
+	}
+
+	var v []checkItemDO
+	if err := json.Unmarshal([]byte(do.CheckItems), &v); err != nil {
+		return nil, err
+	}
+
+	r := make([]domain.CheckItem, len(v))
+	for i := range v {
+		item := &v[i]
+
+		owners, err := toCommunityRoles(item.Owners)
+		if err != nil {
+			return nil, err
+		}
+
+		r[i] = domain.CheckItem{
+			Index:  item.Index,
+			Item:   item.Item,
+			Desc:   item.Desc,
+			Owners: owners,
+			Fields: item.Fields,
+		}
+	}
+
+	return r, nil
+}
+
 func toStringArray(v []domain.SoftwarePkgApprover) (arr pq.StringArray) {
 	arr = make(pq.StringArray, len(v))
 	for i := range v {
@@ -255,3 +397,135 @@ func toEmail(e string) (dp.Email, error) {
 
 	return dp.NewEmail(string(v))
 }
+
+// specDO
+type specDO struct {
+	Name          string   `json:"name"`
+	Version       string   `json:"version"`
+	License       string   `json:"license"`
+	BuildRequires []string `json:"build_requires,omitempty"`
+	Requires      []string `json:"requires,omitempty"`
+	Sources       []string `json:"sources,omitempty"`
+	Patches       []string `json:"patches,omitempty"`
+	Files         []string `json:"files,omitempty"`
+	SubPackages   []string `json:"sub_packages,omitempty"`
+	ExistingDeps  []string `json:"existing_deps,omitempty"`
+}
+
+func toSpecDO(spec *domain.SoftwarePkgSpec) (string, error) {
+	// the pkg was applied before the spec was parsed
+	if spec.Name == "" {
+		return "", nil
+	}
+
+	v := specDO{
+		Name:          spec.Name,
+		Version:       spec.Version,
+		License:       spec.License,
+		BuildRequires: spec.BuildRequires,
+		Requires:      spec.Requires,
+		Sources:       spec.Sources,
+		Patches:       spec.Patches,
+		Files:         spec.Files,
+		SubPackages:   spec.SubPackages,
+		ExistingDeps:  spec.ExistingDeps,
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (do *SoftwarePkgBasicDO) toSpec() (spec domain.SoftwarePkgSpec, err error) {
+	if do.Spec == "" {
+		return
+	}
+
+	var v specDO
+	if err = json.Unmarshal([]byte(do.Spec), &v); err != nil {
+		return
+	}
+
+	spec = domain.SoftwarePkgSpec{
+		Name:          v.Name,
+		Version:       v.Version,
+		License:       v.License,
+		BuildRequires: v.BuildRequires,
+		Requires:      v.Requires,
+		Sources:       v.Sources,
+		Patches:       v.Patches,
+		Files:         v.Files,
+		SubPackages:   v.SubPackages,
+		ExistingDeps:  v.ExistingDeps,
+	}
+
+	return
+}
+
+func toSrcRPMNotesDO(notes []string) (string, error) {
+	if len(notes) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(notes)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (do *SoftwarePkgBasicDO) toSrcRPMNotes() (notes []string, err error) {
+	if do.SrcRPMNotes != "" {
+		err = json.Unmarshal([]byte(do.SrcRPMNotes), &notes)
+	}
+
+	return
+}
+
+// licenseCheckDO
+type licenseCheckDO struct {
+	Index     int    `json:"index"`
+	License   string `json:"license"`
+	Compliant bool   `json:"compliant"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func toLicenseCheckDO(v *domain.LicenseCheck) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+
+	b, err := json.Marshal(licenseCheckDO{
+		Index:     v.Index,
+		License:   v.License,
+		Compliant: v.Compliant,
+		Reason:    v.Reason,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (do *SoftwarePkgBasicDO) toLicenseCheck() (*domain.LicenseCheck, error) {
+	if do.LicenseCheck == "" {
+		return nil, nil
+	}
+
+	var v licenseCheckDO
+	if err := json.Unmarshal([]byte(do.LicenseCheck), &v); err != nil {
+		return nil, err
+	}
+
+	return &domain.LicenseCheck{
+		Index:     v.Index,
+		License:   v.License,
+		Compliant: v.Compliant,
+		Reason:    v.Reason,
+	}, nil
+}
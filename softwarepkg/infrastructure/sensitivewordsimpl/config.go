@@ -1,9 +1,62 @@
 package sensitivewordsimpl
 
+import "errors"
+
+const (
+	// dictionaryModeFallback checks by the dictionary when the cloud service fails.
+	dictionaryModeFallback = "fallback"
+
+	// dictionaryModeChained checks by the dictionary before the cloud service.
+	dictionaryModeChained = "chained"
+)
+
 type Config struct {
 	Endpoint   string `json:"endpoint"       required:"true"`
 	AccessKey  string `json:"access_key"     required:"true"`
 	SecretKey  string `json:"secret_key"     required:"true"`
 	IAMEndpint string `json:"iam_endpoint"   required:"true"`
 	Region     string `json:"region"         required:"true"`
+
+	// FailClosed rejects the content if it can't be checked, such as when
+	// the cloud service fails. The content is accepted by default.
+	FailClosed bool `json:"fail_closed"`
+
+	// Dictionary is the config of the local filter of sensitive words.
+	Dictionary DictionaryConfig `json:"dictionary"`
+}
+
+func (cfg *Config) Validate() error {
+	return cfg.Dictionary.validate()
+}
+
+type DictionaryConfig struct {
+	// Mode is how the dictionary works with the cloud service, which is
+	// fallback or chained. The dictionary is not used if empty.
+	Mode string `json:"mode"`
+
+	// Path is the file of the sensitive words, one word per line.
+	Path string `json:"path"`
+
+	// Words are the sensitive words besides the ones of the file.
+	Words []string `json:"words"`
+}
+
+func (cfg *DictionaryConfig) enabled() bool {
+	return cfg.Mode != ""
+}
+
+func (cfg *DictionaryConfig) validate() error {
+	if !cfg.enabled() {
+		return nil
+	}
+
+	if cfg.Mode != dictionaryModeFallback && cfg.Mode != dictionaryModeChained {
+		return errors.New("invalid mode of the dictionary of sensitive words")
+	}
+
+	if cfg.Path == "" && len(cfg.Words) == 0 {
+		return errors.New("missing the sensitive words of the dictionary")
+	}
+
+	return nil
 }
@@ -0,0 +1,120 @@
+package sensitivewordsimpl
+
+import (
+	"strings"
+	"unicode"
+)
+
+// dictionary finds the sensitive words in the content by the Aho-Corasick
+// automaton, so all the words are matched in one pass. It is case insensitive.
+type dictionary struct {
+	nodes []acNode
+}
+
+type acNode struct {
+	next map[rune]int
+	fail int
+
+	// outputs are the lengths of the words which end at this node,
+	// including the ones of the fail nodes.
+	outputs []int
+}
+
+func newDictionary(words []string) *dictionary {
+	d := &dictionary{
+		nodes: []acNode{{next: map[rune]int{}}},
+	}
+
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			d.add([]rune(strings.ToLower(w)))
+		}
+	}
+
+	d.build()
+
+	return d
+}
+
+func (d *dictionary) add(word []rune) {
+	cur := 0
+
+	for _, r := range word {
+		n, ok := d.nodes[cur].next[r]
+		if !ok {
+			n = len(d.nodes)
+			d.nodes = append(d.nodes, acNode{next: map[rune]int{}})
+			d.nodes[cur].next[r] = n
+		}
+
+		cur = n
+	}
+
+	d.nodes[cur].outputs = append(d.nodes[cur].outputs, len(word))
+}
+
+// build sets the fail links in the order of breadth first, so the fail node
+// of a node is always built before the node.
+func (d *dictionary) build() {
+	queue := make([]int, 0, len(d.nodes))
+
+	for _, n := range d.nodes[0].next {
+		queue = append(queue, n)
+	}
+
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		for r, n := range d.nodes[cur].next {
+			f := d.nodes[cur].fail
+			for f > 0 && !d.has(f, r) {
+				f = d.nodes[f].fail
+			}
+
+			if v, ok := d.nodes[f].next[r]; ok && v != n {
+				d.nodes[n].fail = v
+			}
+
+			d.nodes[n].outputs = append(d.nodes[n].outputs, d.nodes[d.nodes[n].fail].outputs...)
+
+			queue = append(queue, n)
+		}
+	}
+}
+
+func (d *dictionary) has(node int, r rune) bool {
+	_, ok := d.nodes[node].next[r]
+
+	return ok
+}
+
+// match returns the distinct segments of the content which are the words.
+func (d *dictionary) match(content string) []string {
+	text := []rune(content)
+
+	var segments []string
+	seen := map[string]bool{}
+	cur := 0
+
+	for i, r := range text {
+		r = unicode.ToLower(r)
+
+		for cur > 0 && !d.has(cur, r) {
+			cur = d.nodes[cur].fail
+		}
+
+		if n, ok := d.nodes[cur].next[r]; ok {
+			cur = n
+		}
+
+		for _, l := range d.nodes[cur].outputs {
+			if s := string(text[i+1-l : i+1]); !seen[s] {
+				seen[s] = true
+				segments = append(segments, s)
+			}
+		}
+	}
+
+	return segments
+}
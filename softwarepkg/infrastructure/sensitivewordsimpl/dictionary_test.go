@@ -0,0 +1,45 @@
+package sensitivewordsimpl
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDictionaryMatch(t *testing.T) {
+	cases := []struct {
+		name    string
+		words   []string
+		content string
+		want    []string
+	}{
+		{"empty dictionary", nil, "anything", nil},
+		{"blank words are ignored", []string{" ", ""}, "a b", nil},
+		{"no match", []string{"bad"}, "good content", nil},
+		{
+			"overlapping words", []string{"he", "she", "hers", "his"},
+			"ushers", []string{"she", "he", "hers"},
+		},
+		{
+			"case folding keeps the original segments", []string{"Bad"},
+			"so BAD and bad", []string{"BAD", "bad"},
+		},
+		{"duplicated segments", []string{"bad"}, "bad, bad", []string{"bad"}},
+		{
+			"fail link to a shorter word", []string{"abce", "bcd", "c"},
+			"abcd", []string{"c", "bcd"},
+		},
+		{
+			"fail link through several nodes", []string{"aaab", "aab", "ab"},
+			"aaaab", []string{"aaab", "aab", "ab"},
+		},
+		{"multibyte runes", []string{"敏感"}, "这是敏感词", []string{"敏感"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := newDictionary(c.words).match(c.content); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("match(%q) = %q, want %q", c.content, got, c.want)
+			}
+		})
+	}
+}
@@ -2,11 +2,14 @@ package sensitivewordsimpl
 
 import (
 	"errors"
+	"os"
+	"strings"
 
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/auth/basic"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/core/region"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/moderation/v3"
 	"github.com/huaweicloud/huaweicloud-sdk-go-v3/services/moderation/v3/model"
+	"github.com/sirupsen/logrus"
 
 	"github.com/opensourceways/software-package-server/softwarepkg/domain/sensitivewords"
 )
@@ -14,7 +17,10 @@ import (
 var instance *service
 
 type service struct {
-	cli *v3.ModerationClient
+	cli        *v3.ModerationClient
+	dict       *dictionary
+	mode       string
+	failClosed bool
 }
 
 func Init(cfg *Config) error {
@@ -31,16 +37,91 @@ func Init(cfg *Config) error {
 			Build(),
 	)
 
-	instance = &service{cli: cli}
+	instance = &service{cli: cli, failClosed: cfg.FailClosed}
+
+	if !cfg.Dictionary.enabled() {
+		return nil
+	}
+
+	words, err := loadWords(&cfg.Dictionary)
+	if err != nil {
+		return err
+	}
+
+	instance.dict = newDictionary(words)
+	instance.mode = cfg.Dictionary.Mode
 
 	return nil
 }
 
+func loadWords(cfg *DictionaryConfig) ([]string, error) {
+	if cfg.Path == "" {
+		return cfg.Words, nil
+	}
+
+	v, err := os.ReadFile(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(strings.Split(string(v), "\n"), cfg.Words...), nil
+}
+
 func Sensitive() *service {
 	return instance
 }
 
+// CheckSensitiveWords applies the same policy to all the contents when they
+// can't be checked, which is either accepting or rejecting them.
 func (s *service) CheckSensitiveWords(content string) error {
+	err := s.check(content)
+	if err == nil || s.failClosed || sensitivewords.IsErrorSensitiveInfo(err) {
+		return err
+	}
+
+	logrus.Errorf(
+		"failed to check sensitive words, accept the content, err:%s", err.Error(),
+	)
+
+	return nil
+}
+
+func (s *service) check(content string) error {
+	switch s.mode {
+	case dictionaryModeChained:
+		if err := s.checkByDictionary(content); err != nil {
+			return err
+		}
+
+		return s.checkByCloud(content)
+
+	case dictionaryModeFallback:
+		err := s.checkByCloud(content)
+		if err == nil || sensitivewords.IsErrorSensitiveInfo(err) {
+			return err
+		}
+
+		logrus.Errorf(
+			"failed to check sensitive words by the cloud service, check by the dictionary instead, err:%s",
+			err.Error(),
+		)
+
+		return s.checkByDictionary(content)
+
+	default:
+		return s.checkByCloud(content)
+	}
+}
+
+func (s *service) checkByDictionary(content string) error {
+	if v := s.dict.match(content); len(v) > 0 {
+		return newErrorSensitiveInfo(v)
+	}
+
+	return nil
+}
+
+func (s *service) checkByCloud(content string) error {
 	request := &model.RunTextModerationRequest{
 		Body: &model.TextDetectionReq{
 			Data: &model.TextDetectionDataReq{
@@ -56,8 +137,43 @@ func (s *service) CheckSensitiveWords(content string) error {
 	}
 
 	if *resp.Result.Suggestion != "pass" {
-		return sensitivewords.NewErrorSensitiveInfo(errors.New("invalid text"))
+		return newErrorSensitiveInfo(segmentsOfResult(resp.Result))
 	}
 
 	return nil
 }
+
+// segmentsOfResult returns the segments hit. It may be empty if the text is
+// detected by the semantic model.
+func segmentsOfResult(result *model.TextDetectionResult) []string {
+	if result.Details == nil {
+		return nil
+	}
+
+	var r []string
+
+	for _, detail := range *result.Details {
+		if detail.Segments == nil {
+			continue
+		}
+
+		for _, v := range *detail.Segments {
+			if v.Segment != nil {
+				r = append(r, *v.Segment)
+			}
+		}
+	}
+
+	return r
+}
+
+func newErrorSensitiveInfo(segments []string) error {
+	if len(segments) == 0 {
+		return sensitivewords.NewErrorSensitiveInfo(errors.New("invalid text"))
+	}
+
+	return sensitivewords.NewErrorSensitiveInfo(
+		errors.New("invalid text, sensitive words: "+strings.Join(segments, ", ")),
+		segments...,
+	)
+}
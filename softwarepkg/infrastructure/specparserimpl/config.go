@@ -0,0 +1,25 @@
+package specparserimpl
+
+import "github.com/opensourceways/software-package-server/common/infrastructure/httpclient"
+
+type Config struct {
+	httpclient.Config
+
+	// Timeout is the timeout in seconds to fetch the spec file.
+	Timeout int `json:"timeout"`
+
+	// MaxSize is the max size in bytes of the spec file.
+	MaxSize int64 `json:"max_size"`
+}
+
+func (cfg *Config) SetDefault() {
+	cfg.Config.SetDefault()
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10
+	}
+
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = 1 << 20
+	}
+}
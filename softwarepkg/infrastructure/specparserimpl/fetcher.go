@@ -0,0 +1,57 @@
+package specparserimpl
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/opensourceways/software-package-server/common/infrastructure/httpclient"
+)
+
+// fetcher fetches the content of the file at the url.
+type fetcher interface {
+	fetch(*url.URL) ([]byte, error)
+}
+
+func readAtMost(r io.Reader, maxSize int64) ([]byte, error) {
+	v, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(v)) > maxSize {
+		return nil, fmt.Errorf("the file exceeds the max size:%d", maxSize)
+	}
+
+	return v, nil
+}
+
+// httpFetcher
+type httpFetcher struct {
+	cli     *httpclient.Client
+	maxSize int64
+}
+
+func newHttpFetcher(cfg *Config) httpFetcher {
+	return httpFetcher{
+		cli:     httpclient.NewClient(&cfg.Config, time.Duration(cfg.Timeout)*time.Second),
+		maxSize: cfg.MaxSize,
+	}
+}
+
+func (f httpFetcher) fetch(u *url.URL) ([]byte, error) {
+	resp, err := f.cli.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s, status code:%d", u.String(), resp.StatusCode)
+	}
+
+	return readAtMost(resp.Body, f.maxSize)
+}
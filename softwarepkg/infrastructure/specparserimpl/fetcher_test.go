@@ -0,0 +1,28 @@
+package specparserimpl
+
+import (
+	"errors"
+	"net/url"
+	"os"
+)
+
+// fileFetcher reads the local fixture. It is only registered by the tests,
+// because the spec url is given by the applicant.
+type fileFetcher struct {
+	maxSize int64
+}
+
+func (f fileFetcher) fetch(u *url.URL) ([]byte, error) {
+	if u.Path == "" {
+		return nil, errors.New("empty file path")
+	}
+
+	file, err := os.Open(u.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return readAtMost(file, f.maxSize)
+}
@@ -0,0 +1,52 @@
+package specparserimpl
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+var instance *specParserImpl
+
+func Init(cfg *Config) {
+	instance = &specParserImpl{
+		fetchers: map[string]fetcher{
+			"http":  newHttpFetcher(cfg),
+			"https": newHttpFetcher(cfg),
+		},
+	}
+}
+
+func SpecParser() *specParserImpl {
+	return instance
+}
+
+// specParserImpl
+type specParserImpl struct {
+	fetchers map[string]fetcher
+}
+
+func (impl *specParserImpl) Parse(specURL dp.URL) (domain.SoftwarePkgSpec, error) {
+	u, err := url.Parse(specURL.URL())
+	if err != nil {
+		return domain.SoftwarePkgSpec{}, err
+	}
+
+	f, ok := impl.fetchers[u.Scheme]
+	if !ok {
+		return domain.SoftwarePkgSpec{}, errors.New("unsupported scheme of spec url: " + u.Scheme)
+	}
+
+	content, err := f.fetch(u)
+	if err != nil {
+		return domain.SoftwarePkgSpec{}, err
+	}
+
+	return parse(content)
+}
+
+func (impl *specParserImpl) ParseContent(content []byte) (domain.SoftwarePkgSpec, error) {
+	return parse(content)
+}
@@ -0,0 +1,54 @@
+package specparserimpl
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+func TestParseURL(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefault()
+
+	Init(&cfg)
+
+	path, err := filepath.Abs("testdata/foo.spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	specURL, err := dp.NewURL("file://" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SpecParser().Parse(specURL); err == nil {
+		t.Fatal("the local file should not be fetched by the parser of production")
+	}
+
+	impl := specParserImpl{
+		fetchers: map[string]fetcher{
+			"file": fileFetcher{maxSize: cfg.MaxSize},
+		},
+	}
+
+	spec, err := impl.Parse(specURL)
+	if err != nil {
+		t.Fatalf("parse failed, err:%s", err.Error())
+	}
+
+	if spec.Name != "python-foo" {
+		t.Errorf("got name %s, want python-foo", spec.Name)
+	}
+
+	small := specParserImpl{
+		fetchers: map[string]fetcher{
+			"file": fileFetcher{maxSize: 10},
+		},
+	}
+
+	if _, err := small.Parse(specURL); err == nil {
+		t.Error("expect an error for the spec exceeding the max size")
+	}
+}
@@ -0,0 +1,305 @@
+package specparserimpl
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+)
+
+const (
+	maxTimesToExpandMacro = 10
+
+	// maxExpandedSize is the max total size of the content expanded from
+	// the macros, which stops the spec whose macros refer to each other
+	// from growing exponentially.
+	maxExpandedSize = 4 << 20
+)
+
+var errExceedMaxExpandedSize = errors.New("the macros of spec expand too much")
+
+var (
+	reTag   = regexp.MustCompile(`^([A-Za-z]+[0-9]*)(\([^)]*\))?\s*:\s*(.*)$`)
+	reMacro = regexp.MustCompile(`%\{(\??)([A-Za-z_][A-Za-z0-9_]*)\}|%([A-Za-z_][A-Za-z0-9_]*)`)
+
+	// the directives which prefix the path in the %files section.
+	reFileDirective = regexp.MustCompile(`^%(attr|defattr|config|verify|doc|license|dir|ghost|lang|caps)(\([^)]*\))?\s*`)
+
+	sections = map[string]bool{
+		"package": true, "description": true, "prep": true, "build": true,
+		"install": true, "check": true, "clean": true, "files": true,
+		"changelog": true, "pre": true, "post": true, "preun": true,
+		"postun": true, "pretrans": true, "posttrans": true,
+		"triggerin": true, "triggerun": true, "triggerpostun": true,
+		"verifyscript": true, "generate_buildrequires": true,
+	}
+
+	conditionals = map[string]bool{
+		"if": true, "ifarch": true, "ifnarch": true, "ifos": true,
+		"ifnos": true, "else": true, "elif": true, "endif": true,
+	}
+
+	versionOperators = map[string]bool{
+		"=": true, "<": true, ">": true, "<=": true, ">=": true,
+	}
+)
+
+// specParser holds the state of parsing a spec file.
+type specParser struct {
+	spec    domain.SoftwarePkgSpec
+	macros  map[string]string
+	section string
+
+	// expanded is the total size of the content expanded so far.
+	expanded int
+	err      error
+}
+
+// parse extracts the metadata from the content of spec file.
+// The conditionals are not evaluated, so the tags of all the branches
+// are collected.
+func parse(content []byte) (domain.SoftwarePkgSpec, error) {
+	p := specParser{
+		macros: map[string]string{},
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+
+	for scanner.Scan() {
+		p.parseLine(strings.TrimSpace(scanner.Text()))
+
+		if p.err != nil {
+			return domain.SoftwarePkgSpec{}, p.err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return domain.SoftwarePkgSpec{}, err
+	}
+
+	if p.spec.Name == "" {
+		return domain.SoftwarePkgSpec{}, errors.New("missing the name in spec")
+	}
+
+	return p.spec, nil
+}
+
+func (p *specParser) parseLine(line string) {
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+
+	if strings.HasPrefix(line, "%") {
+		directive, args := splitDirective(line[1:])
+
+		switch {
+		case directive == "define" || directive == "global":
+			p.defineMacro(args)
+
+			return
+
+		case conditionals[directive]:
+			return
+
+		case sections[directive]:
+			p.section = directive
+
+			if directive == "package" {
+				p.addSubPackage(args)
+			}
+
+			return
+		}
+	}
+
+	switch p.section {
+	case "", "package":
+		p.parseTag(line)
+
+	case "files":
+		p.parseFile(line)
+	}
+}
+
+func splitDirective(s string) (string, string) {
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i+1:])
+	}
+
+	return s, ""
+}
+
+func (p *specParser) defineMacro(args string) {
+	name, value := splitDirective(args)
+	if name != "" {
+		p.macros[name] = value
+	}
+}
+
+// addSubPackage adds the sub package declared by "%package [-n] name".
+// The name is prefixed by the name of pkg unless -n is set.
+func (p *specParser) addSubPackage(args string) {
+	fields := strings.Fields(p.expand(args))
+
+	name := ""
+	for i := 0; i < len(fields); i++ {
+		if fields[i] == "-n" && i+1 < len(fields) {
+			name = fields[i+1]
+
+			break
+		}
+
+		if !strings.HasPrefix(fields[i], "-") && p.spec.Name != "" {
+			name = p.spec.Name + "-" + fields[i]
+
+			break
+		}
+	}
+
+	if name != "" {
+		p.spec.SubPackages = append(p.spec.SubPackages, name)
+	}
+}
+
+func (p *specParser) parseTag(line string) {
+	m := reTag.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+
+	tag := strings.ToLower(m[1])
+	value := p.expand(strings.TrimSpace(m[3]))
+	spec := &p.spec
+
+	switch {
+	case tag == "name":
+		// the name of sub package is declared by %package
+		if p.section == "" {
+			spec.Name = value
+			p.macros["name"] = value
+		}
+
+	case tag == "version":
+		if p.section == "" {
+			spec.Version = value
+			p.macros["version"] = value
+		}
+
+	case tag == "release":
+		if p.section == "" {
+			p.macros["release"] = value
+		}
+
+	case tag == "license":
+		if spec.License == "" {
+			spec.License = value
+		}
+
+	case tag == "buildrequires":
+		spec.BuildRequires = appendDeps(spec.BuildRequires, value)
+
+	case tag == "requires":
+		spec.Requires = appendDeps(spec.Requires, value)
+
+	case strings.HasPrefix(tag, "source"):
+		spec.Sources = append(spec.Sources, value)
+
+	case strings.HasPrefix(tag, "patch"):
+		spec.Patches = append(spec.Patches, value)
+	}
+}
+
+func (p *specParser) parseFile(line string) {
+	for {
+		loc := reFileDirective.FindStringIndex(line)
+		if loc == nil {
+			break
+		}
+
+		line = line[loc[1]:]
+	}
+
+	if line == "" || strings.HasPrefix(line, "%") && !strings.HasPrefix(line, "%{") {
+		return
+	}
+
+	// a directive such as %doc may be followed by more than one file.
+	for _, f := range strings.Fields(p.expand(line)) {
+		p.spec.Files = append(p.spec.Files, f)
+	}
+}
+
+// expand replaces the macros defined in the spec with their values.
+// The undefined ones are kept unless they are conditional like %{?dist}.
+// It fails if the total expanded content exceeds maxExpandedSize.
+func (p *specParser) expand(s string) string {
+	if p.err != nil {
+		return ""
+	}
+
+	for i := 0; i < maxTimesToExpandMacro && strings.Contains(s, "%"); i++ {
+		size := len(s)
+
+		v := reMacro.ReplaceAllStringFunc(s, func(m string) string {
+			// stop growing as soon as it is too large.
+			if p.expanded+size > maxExpandedSize {
+				return m
+			}
+
+			sub := reMacro.FindStringSubmatch(m)
+
+			name := sub[2]
+			if name == "" {
+				name = sub[3]
+			}
+
+			if value, ok := p.macros[name]; ok {
+				size += len(value) - len(m)
+
+				return value
+			}
+
+			if sub[1] == "?" {
+				return ""
+			}
+
+			return m
+		})
+
+		if p.expanded+size > maxExpandedSize {
+			p.err = errExceedMaxExpandedSize
+
+			return ""
+		}
+
+		if v == s {
+			break
+		}
+
+		s = v
+	}
+
+	p.expanded += len(s)
+
+	return s
+}
+
+// appendDeps appends the dependencies, such as "a >= 1.0, b c", to deps.
+func appendDeps(deps []string, value string) []string {
+	tokens := strings.Fields(strings.ReplaceAll(value, ",", " "))
+
+	for i := 0; i < len(tokens); i++ {
+		if i+2 < len(tokens) && versionOperators[tokens[i+1]] {
+			deps = append(deps, strings.Join(tokens[i:i+3], " "))
+			i += 2
+		} else {
+			deps = append(deps, tokens[i])
+		}
+	}
+
+	return deps
+}
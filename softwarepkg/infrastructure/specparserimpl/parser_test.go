@@ -0,0 +1,164 @@
+package specparserimpl
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content, err := os.ReadFile("testdata/foo.spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := parse(content)
+	if err != nil {
+		t.Fatalf("parse failed, err:%s", err.Error())
+	}
+
+	cases := []struct {
+		field string
+		got   interface{}
+		want  interface{}
+	}{
+		{"Name", spec.Name, "python-foo"},
+		{"Version", spec.Version, "1.2.3"},
+		{"License", spec.License, "MIT AND Apache-2.0"},
+		{
+			"BuildRequires", spec.BuildRequires,
+			[]string{"gcc >= 8.0", "make", "python3-devel", "python3-sphinx"},
+		},
+		{
+			"Requires", spec.Requires,
+			[]string{"python3-six", "python3-requests >= 2.0"},
+		},
+		{
+			"Sources", spec.Sources,
+			[]string{"https://files.pythonhosted.org/foo-1.2.3.tar.gz", "python-foo.conf"},
+		},
+		{"Patches", spec.Patches, []string{"0001-fix-build.patch"}},
+		{"SubPackages", spec.SubPackages, []string{"python3-foo"}},
+		{
+			"Files", spec.Files,
+			[]string{
+				"LICENSE", "README.md", "CHANGELOG.md",
+				"%{_bindir}/foo", "/etc/foo.conf",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s: got %#v, want %#v", c.field, c.got, c.want)
+		}
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		field   func(*specParser) interface{}
+		want    interface{}
+	}{
+		{
+			name:    "macro with braces",
+			content: "%global n bar\nName: lib%{n}",
+			field:   func(p *specParser) interface{} { return p.spec.Name },
+			want:    "libbar",
+		},
+		{
+			name:    "macro without braces",
+			content: "%define v 2.0\nName: bar\nVersion: %v",
+			field:   func(p *specParser) interface{} { return p.spec.Version },
+			want:    "2.0",
+		},
+		{
+			name:    "undefined conditional macro",
+			content: "Name: bar%{?suffix}",
+			field:   func(p *specParser) interface{} { return p.spec.Name },
+			want:    "bar",
+		},
+		{
+			name:    "name of sub package is ignored",
+			content: "Name: bar\n%package devel\nName: bar-devel",
+			field:   func(p *specParser) interface{} { return p.spec.Name },
+			want:    "bar",
+		},
+		{
+			name:    "first license wins",
+			content: "Name: bar\nLicense: MIT\n%package devel\nLicense: GPL-2.0",
+			field:   func(p *specParser) interface{} { return p.spec.License },
+			want:    "MIT",
+		},
+		{
+			name:    "requires with qualifier",
+			content: "Name: bar\nRequires(post): systemd",
+			field:   func(p *specParser) interface{} { return p.spec.Requires },
+			want:    []string{"systemd"},
+		},
+		{
+			name:    "numbered patches",
+			content: "Name: bar\nPatch1: a.patch\nPatch2: b.patch",
+			field:   func(p *specParser) interface{} { return p.spec.Patches },
+			want:    []string{"a.patch", "b.patch"},
+		},
+		{
+			name:    "sub packages",
+			content: "Name: bar\n%package devel\n%package -n %{name}-libs\n%package -n python3-bar",
+			field:   func(p *specParser) interface{} { return p.spec.SubPackages },
+			want:    []string{"bar-devel", "bar-libs", "python3-bar"},
+		},
+		{
+			name:    "tags in other sections are ignored",
+			content: "Name: bar\n%description\nSource0: not-a-source",
+			field:   func(p *specParser) interface{} { return p.spec.Sources },
+			want:    []string(nil),
+		},
+	}
+
+	for _, c := range cases {
+		p := specParser{macros: map[string]string{}}
+
+		for _, line := range strings.Split(c.content, "\n") {
+			p.parseLine(line)
+		}
+
+		if got := c.field(&p); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: got %#v, want %#v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestParseMissingName(t *testing.T) {
+	content, err := os.ReadFile("testdata/noname.spec")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parse(content); err == nil {
+		t.Error("expect an error for the spec without name")
+	}
+}
+
+func TestParseMacrosExpandTooMuch(t *testing.T) {
+	var b strings.Builder
+
+	b.WriteString("%define a0 xxxxxxxxxx\n")
+	for i := 1; i < 10; i++ {
+		fmt.Fprintf(&b, "%%define a%d %s\n", i, strings.Repeat(fmt.Sprintf("%%{a%d}", i-1), 10))
+	}
+
+	b.WriteString("Name: foo\nSummary: %{a9}\n")
+
+	if _, err := parse([]byte(b.String())); err != errExceedMaxExpandedSize {
+		t.Errorf("expect the error of exceeding the max expanded size, got %v", err)
+	}
+
+	if _, err := parse([]byte("%define a bar\nName: foo-%{a}\n")); err != nil {
+		t.Errorf("parse failed, err:%s", err.Error())
+	}
+}
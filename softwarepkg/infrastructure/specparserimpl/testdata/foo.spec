@@ -0,0 +1,49 @@
+%global pypi_name foo
+%define debug_package %{nil}
+
+Name:           python-%{pypi_name}
+Version:        1.2.3
+Release:        1%{?dist}
+Summary:        A fixture of spec
+License:        MIT AND Apache-2.0
+URL:            https://github.com/example/foo
+Source0:        https://files.pythonhosted.org/%{pypi_name}-%{version}.tar.gz
+Source1:        %{name}.conf
+Patch0:         0001-fix-build.patch
+
+BuildRequires:  gcc >= 8.0, make
+BuildRequires:  python3-devel
+Requires:       python3-six
+
+%if 0%{?with_docs}
+BuildRequires:  python3-sphinx
+%endif
+
+%description
+The foo library.
+
+%package        -n python3-%{pypi_name}
+Summary:        The python3 module of foo
+Requires:       python3-requests >= 2.0
+
+%description -n python3-%{pypi_name}
+The python3 module of foo.
+
+%prep
+%autosetup -n %{pypi_name}-%{version}
+
+%build
+%py3_build
+
+%install
+%py3_install
+
+%files -n python3-%{pypi_name}
+%license LICENSE
+%doc README.md CHANGELOG.md
+%attr(0755,root,root) %{_bindir}/foo
+%config(noreplace) /etc/foo.conf
+
+%changelog
+* Mon Jan 01 2024 someone <someone@example.com> - 1.2.3-1
+- Name: not a tag in the changelog
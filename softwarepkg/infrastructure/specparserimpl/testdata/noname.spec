@@ -0,0 +1,5 @@
+Version:        1.0
+License:        MIT
+
+%description
+The spec without a name.
@@ -0,0 +1,216 @@
+package srpmreaderimpl
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+const (
+	headLen = 8
+
+	// maxZipSize is the max size of the zip archive which will be listed.
+	// The zip archive is read into the memory, because its directory is at the end.
+	maxZipSize = 64 << 20
+
+	// compressorZip means the archive is a zip rather than a tar.
+	compressorZip = "zip"
+)
+
+var (
+	binaryMagics = [][]byte{
+		[]byte("\x7fELF"),
+		[]byte("MZ"),
+		[]byte("!<arch>\n"),
+		{0xca, 0xfe, 0xba, 0xbe},
+		{0xfe, 0xed, 0xfa, 0xce},
+		{0xfe, 0xed, 0xfa, 0xcf},
+		{0xce, 0xfa, 0xed, 0xfe},
+		{0xcf, 0xfa, 0xed, 0xfe},
+	}
+
+	// the binaries which can't be recognized by the magic.
+	binaryExts = map[string]bool{
+		".jar": true, ".war": true, ".pyc": true, ".whl": true,
+	}
+
+	// the compressors of the source archives.
+	archiveExts = map[string]string{
+		".tar":     "",
+		".tar.gz":  "gzip",
+		".tgz":     "gzip",
+		".tar.bz2": "bzip2",
+		".tbz2":    "bzip2",
+		".tar.zst": "zstd",
+		".tar.xz":  "xz",
+		".txz":     "xz",
+		".zip":     compressorZip,
+	}
+)
+
+// decompress returns the reader of the data compressed by the compressor.
+func decompress(r io.Reader, compressor string) (io.ReadCloser, error) {
+	switch compressor {
+	case "":
+		return io.NopCloser(r), nil
+
+	case "gzip":
+		return gzip.NewReader(r)
+
+	case "bzip2":
+		return io.NopCloser(bzip2.NewReader(r)), nil
+
+	case "xz":
+		xr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return io.NopCloser(xr), nil
+
+	case "zstd":
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.IOReadCloser(), nil
+	}
+
+	return nil, errors.New("unsupported compressor: " + compressor)
+}
+
+// isBinary checks whether the file is a binary by its name and
+// the head of its content.
+func isBinary(name string, head []byte) bool {
+	if binaryExts[strings.ToLower(path.Ext(name))] {
+		return true
+	}
+
+	for _, m := range binaryMagics {
+		if bytes.HasPrefix(head, m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func readHead(r io.Reader) ([]byte, error) {
+	head := make([]byte, headLen)
+
+	n, err := io.ReadFull(r, head)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+
+	return head[:n], err
+}
+
+// archiveCompressor returns the compressor of the source archive
+// and whether the file is a supported archive.
+func archiveCompressor(name string) (string, bool) {
+	name = strings.ToLower(name)
+
+	for ext, c := range archiveExts {
+		if strings.HasSuffix(name, ext) {
+			return c, true
+		}
+	}
+
+	return "", false
+}
+
+// listArchive lists the regular files in the tar or zip archive of the size.
+func listArchive(r io.Reader, compressor string, size int64, add func(name string, size int64, head []byte) error) error {
+	if compressor == compressorZip {
+		return listZip(r, size, add)
+	}
+
+	dr, err := decompress(r, compressor)
+	if err != nil {
+		return err
+	}
+
+	defer dr.Close()
+
+	tr := tar.NewReader(dr)
+
+	for {
+		h, err := tr.Next()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		head, err := readHead(tr)
+		if err != nil {
+			return err
+		}
+
+		if err = add(h.Name, h.Size, head); err != nil {
+			return err
+		}
+	}
+}
+
+func listZip(r io.Reader, size int64, add func(name string, size int64, head []byte) error) error {
+	if size > maxZipSize {
+		return fmt.Errorf("the zip archive exceeds the max size:%d", maxZipSize)
+	}
+
+	v, err := io.ReadAll(io.LimitReader(r, size))
+	if err != nil {
+		return err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(v), int64(len(v)))
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+
+		head, err := readZipHead(f)
+		if err != nil {
+			return err
+		}
+
+		if err = add(f.Name, int64(f.UncompressedSize64), head); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readZipHead(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	defer rc.Close()
+
+	return readHead(rc)
+}
@@ -0,0 +1,33 @@
+package srpmreaderimpl
+
+import "github.com/opensourceways/software-package-server/common/infrastructure/httpclient"
+
+type Config struct {
+	httpclient.Config
+
+	// Timeout is the timeout in seconds to download the src.rpm.
+	Timeout int `json:"timeout"`
+
+	// MaxSize is the max size in bytes of the src.rpm.
+	MaxSize int64 `json:"max_size"`
+
+	// MaxFiles is the max number of files to list, including the ones
+	// in the source archives.
+	MaxFiles int `json:"max_files"`
+}
+
+func (cfg *Config) SetDefault() {
+	cfg.Config.SetDefault()
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 300
+	}
+
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = 500 << 20
+	}
+
+	if cfg.MaxFiles <= 0 {
+		cfg.MaxFiles = 100000
+	}
+}
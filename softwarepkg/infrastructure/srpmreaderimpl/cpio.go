@@ -0,0 +1,124 @@
+package srpmreaderimpl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	cpioHeaderLen = 110
+	cpioTrailer   = "TRAILER!!!"
+	cpioMaxName   = 4096
+
+	modeTypeMask = 0170000
+	modeRegular  = 0100000
+)
+
+// cpioEntry
+type cpioEntry struct {
+	name string
+	size int64
+	mode int64
+}
+
+func (e *cpioEntry) isRegular() bool {
+	return e.mode&modeTypeMask == modeRegular
+}
+
+// cpioReader reads the cpio archive of "newc" format which is the payload of rpm.
+type cpioReader struct {
+	r         io.Reader
+	remaining int64
+	pad       int64
+}
+
+func padding(n int64) int64 {
+	return (4 - n%4) % 4
+}
+
+// next skips the rest of current entry and returns the next one.
+// It returns io.EOF at the end of archive.
+func (c *cpioReader) next() (e cpioEntry, err error) {
+	if n := c.remaining + c.pad; n > 0 {
+		if _, err = io.CopyN(io.Discard, c.r, n); err != nil {
+			return
+		}
+	}
+
+	c.remaining, c.pad = 0, 0
+
+	header := make([]byte, cpioHeaderLen)
+	if _, err = io.ReadFull(c.r, header); err != nil {
+		return
+	}
+
+	if magic := string(header[:6]); magic != "070701" && magic != "070702" {
+		err = errors.New("unsupported format of cpio")
+
+		return
+	}
+
+	field := func(offset int) (int64, error) {
+		return strconv.ParseInt(string(header[offset:offset+8]), 16, 64)
+	}
+
+	if e.mode, err = field(14); err != nil {
+		return
+	}
+
+	if e.size, err = field(54); err != nil {
+		return
+	}
+
+	nameSize, err := field(94)
+	if err != nil {
+		return
+	}
+
+	if nameSize <= 0 || nameSize > cpioMaxName {
+		err = fmt.Errorf("invalid name size:%d of cpio", nameSize)
+
+		return
+	}
+
+	name := make([]byte, nameSize+padding(cpioHeaderLen+nameSize))
+	if _, err = io.ReadFull(c.r, name); err != nil {
+		return
+	}
+
+	// the name is ended with NUL
+	e.name = string(name[:nameSize-1])
+
+	if e.name == cpioTrailer {
+		err = io.EOF
+
+		return
+	}
+
+	c.remaining = e.size
+	c.pad = padding(e.size)
+
+	return
+}
+
+// Read reads the data of current entry.
+func (c *cpioReader) Read(p []byte) (int, error) {
+	if c.remaining <= 0 {
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+
+	if err == io.EOF && c.remaining > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return n, err
+}
@@ -0,0 +1,96 @@
+package srpmreaderimpl
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+type cpioFile struct {
+	name    string
+	mode    int64
+	content []byte
+}
+
+// newCpio builds a cpio archive of newc format.
+func newCpio(files []cpioFile) []byte {
+	var b bytes.Buffer
+
+	write := func(name string, mode int64, content []byte) {
+		fmt.Fprintf(
+			&b, "070701%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x",
+			0, mode, 0, 0, 1, 0, len(content), 0, 0, 0, 0, len(name)+1, 0,
+		)
+
+		b.WriteString(name)
+		b.WriteByte(0)
+		b.Write(make([]byte, padding(int64(cpioHeaderLen+len(name)+1))))
+
+		b.Write(content)
+		b.Write(make([]byte, padding(int64(len(content)))))
+	}
+
+	for _, f := range files {
+		write(f.name, f.mode, f.content)
+	}
+
+	write(cpioTrailer, 0, nil)
+
+	return b.Bytes()
+}
+
+func TestCpioReader(t *testing.T) {
+	files := []cpioFile{
+		{"./a.spec", modeRegular | 0644, []byte("Name: a\n")},
+		{"./dir", 0040000 | 0755, nil},
+		{"./b", modeRegular | 0644, []byte("12345")},
+		{"./empty", modeRegular | 0644, nil},
+	}
+
+	c := cpioReader{r: bytes.NewReader(newCpio(files))}
+
+	for i, f := range files {
+		e, err := c.next()
+		if err != nil {
+			t.Fatalf("entry %d: next failed, err:%s", i, err.Error())
+		}
+
+		if e.name != f.name || e.size != int64(len(f.content)) {
+			t.Errorf("entry %d: got %s/%d, want %s/%d", i, e.name, e.size, f.name, len(f.content))
+		}
+
+		if e.isRegular() != (f.mode&modeTypeMask == modeRegular) {
+			t.Errorf("entry %d: wrong type", i)
+		}
+
+		// skip the content of the second file to check next skips it.
+		if i == 2 {
+			continue
+		}
+
+		v, err := io.ReadAll(&c)
+		if err != nil {
+			t.Fatalf("entry %d: read failed, err:%s", i, err.Error())
+		}
+
+		if !bytes.Equal(v, f.content) {
+			t.Errorf("entry %d: got content %q, want %q", i, v, f.content)
+		}
+	}
+
+	if _, err := c.next(); err != io.EOF {
+		t.Errorf("expect io.EOF at the trailer, got %v", err)
+	}
+}
+
+func TestCpioReaderInvalidMagic(t *testing.T) {
+	data := newCpio([]cpioFile{{"a", modeRegular, nil}})
+	data[0] = 'x'
+
+	c := cpioReader{r: bytes.NewReader(data)}
+
+	if _, err := c.next(); err == nil || err == io.EOF {
+		t.Errorf("expect an error for the invalid magic, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package srpmreaderimpl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opensourceways/software-package-server/common/infrastructure/httpclient"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/specparser"
+)
+
+const maxSpecSize = 1 << 20
+
+var (
+	instance *srcRPMReaderImpl
+
+	errTooManyFiles  = errors.New("too many files")
+	errExceedMaxSize = errors.New("the src.rpm exceeds the max size")
+)
+
+func Init(cfg *Config, parser specparser.SpecParser) {
+	instance = &srcRPMReaderImpl{
+		cli:      httpclient.NewClient(&cfg.Config, time.Duration(cfg.Timeout)*time.Second),
+		parser:   parser,
+		maxSize:  cfg.MaxSize,
+		maxFiles: cfg.MaxFiles,
+	}
+}
+
+func SrcRPMReader() *srcRPMReaderImpl {
+	return instance
+}
+
+// srcRPMReaderImpl
+type srcRPMReaderImpl struct {
+	cli      *httpclient.Client
+	parser   specparser.SpecParser
+	maxSize  int64
+	maxFiles int
+}
+
+func (impl *srcRPMReaderImpl) Read(srpmURL dp.URL) (domain.SoftwarePkgSrcRPM, error) {
+	r, err := impl.open(srpmURL.URL())
+	if err != nil {
+		return domain.SoftwarePkgSrcRPM{}, err
+	}
+
+	defer r.Close()
+
+	return impl.read(&sizeLimitedReader{r: r, remaining: impl.maxSize})
+}
+
+// open downloads the src.rpm. Only the allowed hosts can be fetched,
+// because the url is given by the applicant.
+func (impl *srcRPMReaderImpl) open(v string) (io.ReadCloser, error) {
+	resp, err := impl.cli.Get(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+
+		return nil, fmt.Errorf("failed to download %s, status code:%d", v, resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
+func (impl *srcRPMReaderImpl) read(r io.Reader) (srpm domain.SoftwarePkgSrcRPM, err error) {
+	if err = readLead(r); err != nil {
+		return
+	}
+
+	// signature
+	if _, err = readHeader(r, true); err != nil {
+		return
+	}
+
+	h, err := readHeader(r, false)
+	if err != nil {
+		return
+	}
+
+	if f := h.getString(tagPayloadFormat); f != "" && f != "cpio" {
+		err = errors.New("unsupported payload format: " + f)
+
+		return
+	}
+
+	// the payload is compressed by gzip if the compressor is not set.
+	compressor := h.getString(tagPayloadCompressor)
+	if compressor == "" {
+		compressor = "gzip"
+	}
+
+	payload, err := decompress(r, compressor)
+	if err != nil {
+		return
+	}
+
+	defer payload.Close()
+
+	err = impl.readPayload(&cpioReader{r: payload}, &srpm)
+
+	return
+}
+
+func (impl *srcRPMReaderImpl) readPayload(c *cpioReader, srpm *domain.SoftwarePkgSrcRPM) error {
+	add := func(name string, size int64, head []byte) error {
+		if len(srpm.Files) >= impl.maxFiles {
+			return errTooManyFiles
+		}
+
+		srpm.Files = append(srpm.Files, domain.SrcRPMFile{
+			Path:     name,
+			Size:     size,
+			IsBinary: isBinary(name, head),
+		})
+
+		return nil
+	}
+
+	for {
+		e, err := c.next()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+
+			return err
+		}
+
+		if !e.isRegular() {
+			continue
+		}
+
+		name := strings.TrimPrefix(e.name, "./")
+
+		if strings.HasSuffix(name, ".spec") && srpm.Spec == nil {
+			if err = impl.readSpec(c, srpm); err != nil {
+				return err
+			}
+
+			if err = add(name, e.size, nil); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if compressor, ok := archiveCompressor(name); ok {
+			if err = add(name, e.size, nil); err != nil {
+				return err
+			}
+
+			err = listArchive(c, compressor, e.size, func(file string, size int64, head []byte) error {
+				return add(name+"/"+file, size, head)
+			})
+
+			// only the files of src.rpm are listed if the archive can't be read.
+			if err == errTooManyFiles {
+				return err
+			}
+
+			continue
+		}
+
+		head, err := readHead(c)
+		if err != nil {
+			return err
+		}
+
+		if err = add(name, e.size, head); err != nil {
+			return err
+		}
+	}
+}
+
+func (impl *srcRPMReaderImpl) readSpec(r io.Reader, srpm *domain.SoftwarePkgSrcRPM) error {
+	content, err := io.ReadAll(io.LimitReader(r, maxSpecSize))
+	if err != nil {
+		return err
+	}
+
+	spec, err := impl.parser.ParseContent(content)
+	if err != nil {
+		return err
+	}
+
+	srpm.Spec = &spec
+
+	return nil
+}
+
+// sizeLimitedReader fails if more than the limited bytes are read.
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, errExceedMaxSize
+	}
+
+	// read one more byte to know whether the limit is exceeded.
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.r.Read(p)
+
+	if int64(n) > l.remaining {
+		l.remaining = -1
+
+		// the bytes beyond the limit are dropped, so the error is returned
+		// even if the caller doesn't check it after filling its buffer.
+		return n - 1, errExceedMaxSize
+	}
+
+	l.remaining -= int64(n)
+
+	return n, err
+}
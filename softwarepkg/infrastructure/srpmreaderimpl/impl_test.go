@@ -0,0 +1,221 @@
+package srpmreaderimpl
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/ulikunitz/xz"
+
+	"github.com/opensourceways/software-package-server/softwarepkg/domain"
+	"github.com/opensourceways/software-package-server/softwarepkg/domain/dp"
+)
+
+var elf = []byte("\x7fELF\x02\x01\x01\x00binary")
+
+// fakeSpecParser takes the content of spec as the name.
+type fakeSpecParser struct{}
+
+func (p fakeSpecParser) Parse(dp.URL) (domain.SoftwarePkgSpec, error) {
+	return domain.SoftwarePkgSpec{}, errors.New("unsupported")
+}
+
+func (p fakeSpecParser) ParseContent(content []byte) (domain.SoftwarePkgSpec, error) {
+	return domain.SoftwarePkgSpec{Name: string(content)}, nil
+}
+
+func newTar(files map[string][]byte, compress func(io.Writer) io.WriteCloser) []byte {
+	var b bytes.Buffer
+
+	w := compress(&b)
+	tw := tar.NewWriter(w)
+
+	for _, name := range sortedNames(files) {
+		_ = tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(files[name])),
+			Typeflag: tar.TypeReg,
+		})
+		_, _ = tw.Write(files[name])
+	}
+
+	_ = tw.Close()
+	_ = w.Close()
+
+	return b.Bytes()
+}
+
+func newZip(files map[string][]byte) []byte {
+	var b bytes.Buffer
+
+	zw := zip.NewWriter(&b)
+	for _, name := range sortedNames(files) {
+		w, _ := zw.Create(name)
+		_, _ = w.Write(files[name])
+	}
+
+	_ = zw.Close()
+
+	return b.Bytes()
+}
+
+func sortedNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func newSrcRPM(t *testing.T, files []cpioFile) []byte {
+	var payload bytes.Buffer
+
+	gw := gzip.NewWriter(&payload)
+	_, _ = gw.Write(newCpio(files))
+	_ = gw.Close()
+
+	var b bytes.Buffer
+	b.Write(newLead(rpmTypeSource))
+	b.Write(newHeader(nil, true))
+	b.Write(newHeader(map[uint32]string{
+		tagPayloadFormat:     "cpio",
+		tagPayloadCompressor: "gzip",
+	}, false))
+	b.Write(payload.Bytes())
+
+	return b.Bytes()
+}
+
+func TestRead(t *testing.T) {
+	tgz := newTar(
+		map[string][]byte{"foo/main.c": []byte("int main"), "foo/bin/tool": elf},
+		func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) },
+	)
+
+	txz := newTar(
+		map[string][]byte{"bar/COPYING": []byte("GPL")},
+		func(w io.Writer) io.WriteCloser {
+			xw, err := xz.NewWriter(w)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return xw
+		},
+	)
+
+	plain := newTar(
+		map[string][]byte{"baz/README": []byte("readme")},
+		func(w io.Writer) io.WriteCloser { return nopWriteCloser{w} },
+	)
+
+	zipped := newZip(map[string][]byte{"lib/dep.jar": []byte("PK"), "lib/LICENSE": []byte("MIT")})
+
+	data := newSrcRPM(t, []cpioFile{
+		{"foo.spec", modeRegular | 0644, []byte("foo")},
+		{"foo-1.0.tar.gz", modeRegular | 0644, tgz},
+		{"bar-1.0.tar.xz", modeRegular | 0644, txz},
+		{"baz-1.0.tar", modeRegular | 0644, plain},
+		{"lib-1.0.zip", modeRegular | 0644, zipped},
+		{"prebuilt.so", modeRegular | 0755, elf},
+	})
+
+	impl := srcRPMReaderImpl{
+		parser:   fakeSpecParser{},
+		maxSize:  int64(len(data)),
+		maxFiles: 100,
+	}
+
+	srpm, err := impl.read(&sizeLimitedReader{r: bytes.NewReader(data), remaining: impl.maxSize})
+	if err != nil {
+		t.Fatalf("read failed, err:%s", err.Error())
+	}
+
+	if srpm.Spec == nil || srpm.Spec.Name != "foo" {
+		t.Errorf("the spec is not parsed, got %v", srpm.Spec)
+	}
+
+	want := []domain.SrcRPMFile{
+		{Path: "foo.spec", Size: 3},
+		{Path: "foo-1.0.tar.gz", Size: int64(len(tgz))},
+		{Path: "foo-1.0.tar.gz/foo/bin/tool", Size: int64(len(elf)), IsBinary: true},
+		{Path: "foo-1.0.tar.gz/foo/main.c", Size: 8},
+		{Path: "bar-1.0.tar.xz", Size: int64(len(txz))},
+		{Path: "bar-1.0.tar.xz/bar/COPYING", Size: 3},
+		{Path: "baz-1.0.tar", Size: int64(len(plain))},
+		{Path: "baz-1.0.tar/baz/README", Size: 6},
+		{Path: "lib-1.0.zip", Size: int64(len(zipped))},
+		{Path: "lib-1.0.zip/lib/LICENSE", Size: 3},
+		{Path: "lib-1.0.zip/lib/dep.jar", Size: 2, IsBinary: true},
+		{Path: "prebuilt.so", Size: int64(len(elf)), IsBinary: true},
+	}
+
+	if !reflect.DeepEqual(srpm.Files, want) {
+		t.Errorf("got files:\n%v\nwant:\n%v", srpm.Files, want)
+	}
+
+	if _, err := impl.read(&sizeLimitedReader{r: bytes.NewReader(data), remaining: 100}); err == nil {
+		t.Error("expect an error for the src.rpm exceeding the max size")
+	}
+
+	impl.maxFiles = 3
+	if _, err := impl.read(bytes.NewReader(data)); err != errTooManyFiles {
+		t.Errorf("expect the error of too many files, got %v", err)
+	}
+}
+
+func TestOpenLocalFile(t *testing.T) {
+	cfg := Config{}
+	cfg.SetDefault()
+
+	Init(&cfg, nil)
+
+	if _, err := SrcRPMReader().open("file:///etc/passwd"); err == nil {
+		t.Error("the local file should not be opened")
+	}
+
+	if _, err := SrcRPMReader().open("http://169.254.169.254/latest/meta-data"); err == nil {
+		t.Error("the host which is not allowed should not be fetched")
+	}
+}
+
+func TestArchiveCompressor(t *testing.T) {
+	cases := []struct {
+		name       string
+		compressor string
+		ok         bool
+	}{
+		{"a.tar", "", true},
+		{"a.tar.gz", "gzip", true},
+		{"a.TGZ", "gzip", true},
+		{"a.tar.bz2", "bzip2", true},
+		{"a.tar.zst", "zstd", true},
+		{"a.tar.xz", "xz", true},
+		{"a.txz", "xz", true},
+		{"a.zip", compressorZip, true},
+		{"a.patch", "", false},
+	}
+
+	for _, c := range cases {
+		compressor, ok := archiveCompressor(c.name)
+		if compressor != c.compressor || ok != c.ok {
+			t.Errorf("%s: got %q/%v, want %q/%v", c.name, compressor, ok, c.compressor, c.ok)
+		}
+	}
+}
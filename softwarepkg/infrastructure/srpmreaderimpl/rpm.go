@@ -0,0 +1,132 @@
+package srpmreaderimpl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	leadSize       = 96
+	rpmTypeSource  = 1
+	maxHeaderSize  = 32 << 20
+	maxIndexCount  = 1 << 16
+	headerIntroLen = 16
+	indexEntryLen  = 16
+
+	tagPayloadFormat     = 1124
+	tagPayloadCompressor = 1125
+
+	typeString = 6
+)
+
+var (
+	leadMagic   = []byte{0xed, 0xab, 0xee, 0xdb}
+	headerMagic = []byte{0x8e, 0xad, 0xe8, 0x01}
+)
+
+// readLead reads the lead of rpm and checks it is a src.rpm.
+func readLead(r io.Reader) error {
+	buf := make([]byte, leadSize)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(buf[:4], leadMagic) {
+		return errors.New("not a rpm file")
+	}
+
+	if binary.BigEndian.Uint16(buf[6:8]) != rpmTypeSource {
+		return errors.New("not a src.rpm file")
+	}
+
+	return nil
+}
+
+// indexEntry
+type indexEntry struct {
+	tag    uint32
+	typ    uint32
+	offset uint32
+	count  uint32
+}
+
+// rpmHeader is the header structure of rpm, which is used by both the
+// signature and the header.
+type rpmHeader struct {
+	entries []indexEntry
+	store   []byte
+}
+
+// readHeader reads a header structure. The signature is padded to
+// the 8 bytes boundary.
+func readHeader(r io.Reader, padded bool) (h rpmHeader, err error) {
+	intro := make([]byte, headerIntroLen)
+	if _, err = io.ReadFull(r, intro); err != nil {
+		return
+	}
+
+	if !bytes.Equal(intro[:4], headerMagic) {
+		err = errors.New("invalid magic of rpm header")
+
+		return
+	}
+
+	n := binary.BigEndian.Uint32(intro[8:12])
+	size := binary.BigEndian.Uint32(intro[12:16])
+
+	if n > maxIndexCount || size > maxHeaderSize {
+		err = fmt.Errorf("the rpm header is too large, index count:%d, size:%d", n, size)
+
+		return
+	}
+
+	index := make([]byte, n*indexEntryLen)
+	if _, err = io.ReadFull(r, index); err != nil {
+		return
+	}
+
+	h.entries = make([]indexEntry, n)
+	for i := range h.entries {
+		b := index[i*indexEntryLen:]
+
+		h.entries[i] = indexEntry{
+			tag:    binary.BigEndian.Uint32(b[0:4]),
+			typ:    binary.BigEndian.Uint32(b[4:8]),
+			offset: binary.BigEndian.Uint32(b[8:12]),
+			count:  binary.BigEndian.Uint32(b[12:16]),
+		}
+	}
+
+	h.store = make([]byte, size)
+	if _, err = io.ReadFull(r, h.store); err != nil {
+		return
+	}
+
+	if pad := (8 - size%8) % 8; padded && pad > 0 {
+		_, err = io.CopyN(io.Discard, r, int64(pad))
+	}
+
+	return
+}
+
+func (h *rpmHeader) getString(tag uint32) string {
+	for i := range h.entries {
+		e := &h.entries[i]
+
+		if e.tag != tag || e.typ != typeString || int(e.offset) >= len(h.store) {
+			continue
+		}
+
+		v := h.store[e.offset:]
+		if end := bytes.IndexByte(v, 0); end >= 0 {
+			v = v[:end]
+		}
+
+		return string(v)
+	}
+
+	return ""
+}
@@ -0,0 +1,127 @@
+package srpmreaderimpl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func newLead(rpmType uint16) []byte {
+	lead := make([]byte, leadSize)
+	copy(lead, leadMagic)
+	lead[4] = 3
+	binary.BigEndian.PutUint16(lead[6:8], rpmType)
+
+	return lead
+}
+
+// newHeader builds a header structure with the string tags. The store is
+// padded to the 8 bytes boundary if padded is true.
+func newHeader(tags map[uint32]string, padded bool) []byte {
+	var index, store bytes.Buffer
+
+	// the order of tags is stable, so the result is deterministic.
+	for _, tag := range []uint32{tagPayloadFormat, tagPayloadCompressor} {
+		v, ok := tags[tag]
+		if !ok {
+			continue
+		}
+
+		entry := make([]byte, indexEntryLen)
+		binary.BigEndian.PutUint32(entry[0:4], tag)
+		binary.BigEndian.PutUint32(entry[4:8], typeString)
+		binary.BigEndian.PutUint32(entry[8:12], uint32(store.Len()))
+		binary.BigEndian.PutUint32(entry[12:16], 1)
+		index.Write(entry)
+
+		store.WriteString(v)
+		store.WriteByte(0)
+	}
+
+	intro := make([]byte, headerIntroLen)
+	copy(intro, headerMagic)
+	binary.BigEndian.PutUint32(intro[8:12], uint32(index.Len()/indexEntryLen))
+	binary.BigEndian.PutUint32(intro[12:16], uint32(store.Len()))
+
+	var b bytes.Buffer
+	b.Write(intro)
+	b.Write(index.Bytes())
+	b.Write(store.Bytes())
+
+	if pad := (8 - store.Len()%8) % 8; padded {
+		b.Write(make([]byte, pad))
+	}
+
+	return b.Bytes()
+}
+
+func TestReadLead(t *testing.T) {
+	notRPM := newLead(rpmTypeSource)
+	notRPM[0] = 0
+
+	cases := []struct {
+		name  string
+		lead  []byte
+		valid bool
+	}{
+		{"src.rpm", newLead(rpmTypeSource), true},
+		{"binary rpm", newLead(0), false},
+		{"not rpm", notRPM, false},
+		{"truncated", newLead(rpmTypeSource)[:10], false},
+	}
+
+	for _, c := range cases {
+		err := readLead(bytes.NewReader(c.lead))
+		if (err == nil) != c.valid {
+			t.Errorf("%s: got err %v, want valid %v", c.name, err, c.valid)
+		}
+	}
+}
+
+func TestReadHeader(t *testing.T) {
+	tags := map[uint32]string{
+		tagPayloadFormat:     "cpio",
+		tagPayloadCompressor: "xz",
+	}
+
+	for _, padded := range []bool{true, false} {
+		// the byte after the header must be the next one to read.
+		data := append(newHeader(tags, padded), 0xff)
+		r := bytes.NewReader(data)
+
+		h, err := readHeader(r, padded)
+		if err != nil {
+			t.Fatalf("padded:%v, read header failed, err:%s", padded, err.Error())
+		}
+
+		if v := h.getString(tagPayloadFormat); v != "cpio" {
+			t.Errorf("padded:%v, got payload format %q", padded, v)
+		}
+
+		if v := h.getString(tagPayloadCompressor); v != "xz" {
+			t.Errorf("padded:%v, got payload compressor %q", padded, v)
+		}
+
+		if v := h.getString(1000); v != "" {
+			t.Errorf("padded:%v, got %q for the missing tag", padded, v)
+		}
+
+		if b, err := r.ReadByte(); err != nil || b != 0xff {
+			t.Errorf("padded:%v, the header is not read exactly", padded)
+		}
+	}
+
+	bad := newHeader(tags, false)
+	bad[0] = 0
+
+	if _, err := readHeader(bytes.NewReader(bad), false); err == nil {
+		t.Error("expect an error for the invalid magic")
+	}
+
+	huge := newHeader(tags, false)
+	binary.BigEndian.PutUint32(huge[12:16], maxHeaderSize+1)
+
+	if _, err := readHeader(bytes.NewReader(huge), false); err == nil {
+		t.Error("expect an error for the too large header")
+	}
+}
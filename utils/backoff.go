@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"context"
+	"time"
+)
+
+// BackoffConfig
+type BackoffConfig struct {
+	// Interval is the interval in seconds between two runs.
+	Interval int `json:"interval"`
+
+	// MaxInterval is the max interval in seconds. The interval will be
+	// doubled up to it after each failed run.
+	MaxInterval int `json:"max_interval"`
+}
+
+func (cfg *BackoffConfig) SetDefault() {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 1
+	}
+
+	if cfg.MaxInterval < cfg.Interval {
+		cfg.MaxInterval = 60 * cfg.Interval
+	}
+}
+
+// RunWithBackoff runs f periodically until ctx is done. The interval
+// will be backed off exponentially when f fails and be reset when it succeeds.
+func RunWithBackoff(ctx context.Context, cfg *BackoffConfig, f func() error) {
+	min := time.Duration(cfg.Interval) * time.Second
+	max := time.Duration(cfg.MaxInterval) * time.Second
+
+	interval := min
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-time.After(interval):
+			if err := f(); err == nil {
+				interval = min
+			} else if interval *= 2; interval > max {
+				interval = max
+			}
+		}
+	}
+}